@@ -1,9 +1,13 @@
 package main
 
 import (
+	"bufio"
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"gin-app/config"
+	"gin-app/internal/models"
 	"gin-app/internal/utils"
 	"log"
 	"os"
@@ -12,6 +16,7 @@ import (
 	"time"
 
 	"github.com/pressly/goose"
+	"golang.org/x/term"
 )
 
 // This template is for creating a basic controller in a Gin web application
@@ -102,7 +107,12 @@ const migrationTemplate = `
 `
 
 func main() {
-	if len(os.Args) < 2 {
+	// Same flag as the server; it goes before the command
+	flag.StringVar(&config.ConfigFile, "config", config.ConfigFile, "path to config.yaml (profiles config.<APP_ENV>.yaml are read from the same folder)")
+	flag.Parse()
+	args := flag.Args()
+
+	if len(args) < 1 {
 		fmt.Println("Usage:")
 		fmt.Println("  go run cmd/commands/make.go make:controller ControllerName")
 		fmt.Println("  go run cmd/commands/make.go make:model ModelName")
@@ -111,21 +121,26 @@ func main() {
 		fmt.Println("  go run cmd/commands/make.go migrate:up")
 		fmt.Println("  go run cmd/commands/make.go migrate:down")
 		fmt.Println("  go run cmd/commands/make.go migrate:status")
+		fmt.Println("  go run cmd/commands/make.go db:seed")
+		fmt.Println("  go run cmd/commands/make.go admin:create --name \"Super Admin\" --email admin@example.com [--password-stdin]")
+		fmt.Println("  go run cmd/commands/make.go jwt:rotate")
+		fmt.Println("Flags (before the command):")
+		fmt.Println("  --config path/to/config.yaml   (default ./config/config.yaml or $APP_CONFIG)")
 		return
 	}
 
-	command := os.Args[1]
+	command := args[0]
 
 	// Only load name if the command requires it
 	var name string
-	if len(os.Args) >= 3 {
-		name = os.Args[2]
+	if len(args) >= 2 {
+		name = args[1]
 	}
 
 	// for migration task
 	config.LoadConfig()
 	dsn := config.GetDSN() // ✅ use same DSN everywhere
-	dir := "./migrations"
+	dir := config.AppConfig.Database.MigrationsDir
 
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
@@ -163,6 +178,16 @@ func main() {
 		fmt.Println("✅ Migration rolled back!")
 	case "migrate:status":
 		_ = goose.Status(db, dir)
+	case "db:seed":
+		config.InitDB()
+		if err := models.SeedRBAC(config.Ctx, config.DB); err != nil {
+			log.Fatal("❌ Seeding failed: ", err)
+		}
+		fmt.Println("✅ Roles and permissions seeded!")
+	case "admin:create":
+		createAdmin(args[1:])
+	case "jwt:rotate":
+		rotateJWTKeys()
 	default:
 		fmt.Println("❌ Unknown command:", command)
 	}
@@ -284,3 +309,112 @@ func createMigration(name string) {
 }
 
 // Migration run command
+
+// New JWT signing key; running instances pick it up within a minute and the
+// previous key keeps verifying for jwt.grace_period
+func rotateJWTKeys() {
+	keys, err := utils.LoadJWTKeys()
+	if err != nil {
+		log.Fatal("❌ Failed to load JWT keys: ", err)
+	}
+	key, err := keys.Rotate()
+	if err != nil {
+		log.Fatal("❌ Failed to rotate JWT key: ", err)
+	}
+	removed, err := keys.Prune()
+	if err != nil {
+		log.Fatal("❌ Failed to remove expired JWT keys: ", err)
+	}
+	fmt.Printf("✅ New %s signing key %s (%d expired keys removed)\n", key.Algorithm, key.ID, removed)
+}
+
+// Super admin create
+//
+// The password is taken from $ADMIN_PASSWORD, from stdin (--password-stdin or
+// when stdin is not a terminal) or prompted for twice without echo.
+func createAdmin(args []string) {
+	fs := flag.NewFlagSet("admin:create", flag.ExitOnError)
+	name := fs.String("name", "", "display name of the admin")
+	email := fs.String("email", "", "login email of the admin")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	_ = fs.Parse(args)
+
+	*name = strings.TrimSpace(*name)
+	*email = strings.ToLower(strings.TrimSpace(*email))
+	if *name == "" || *email == "" {
+		log.Fatal("❌ Please provide --name and --email")
+	}
+	if !strings.Contains(*email, "@") {
+		log.Fatal("❌ Please provide a valid --email")
+	}
+
+	password, err := readAdminPassword(*passwordStdin)
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+	if err := utils.ValidatePasswordStrength(password); err != nil {
+		log.Fatal("❌ Weak password: ", err)
+	}
+
+	config.InitDB()
+
+	exists, err := config.DB.NewSelect().Model((*models.User)(nil)).Where("email = ?", *email).Exists(config.Ctx)
+	if err != nil {
+		log.Fatal("❌ ", err)
+	}
+	if exists {
+		log.Fatal("❌ A user with this email already exists")
+	}
+
+	hashed, err := models.HashPassword(password)
+	if err != nil {
+		log.Fatal("❌ Failed to hash password: ", err)
+	}
+
+	user := models.User{
+		Name:     *name,
+		Email:    *email,
+		Password: hashed,
+		Role:     models.RoleAdmin,
+		Status:   models.UserStatusActive,
+	}
+	user.BeforeInsert()
+
+	if _, err := config.DB.NewInsert().Model(&user).Exec(config.Ctx); err != nil {
+		log.Fatal("❌ Failed to create admin: ", err)
+	}
+
+	fmt.Printf("✅ Admin created: #%d %s <%s>\n", user.ID, user.Name, user.Email)
+}
+
+func readAdminPassword(fromStdin bool) (string, error) {
+	if password := os.Getenv("ADMIN_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	stdin := int(os.Stdin.Fd())
+	if fromStdin || !term.IsTerminal(stdin) {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", errors.New("could not read password from stdin")
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	fmt.Print("Password: ")
+	first, err := term.ReadPassword(stdin)
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+	fmt.Print("Confirm password: ")
+	second, err := term.ReadPassword(stdin)
+	fmt.Println()
+	if err != nil {
+		return "", err
+	}
+	if string(first) != string(second) {
+		return "", errors.New("passwords do not match")
+	}
+	return string(first), nil
+}
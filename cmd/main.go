@@ -1,13 +1,107 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"gin-app/config"
+	"gin-app/internal/pkg/metrics"
 	"gin-app/internal/pkg/router"
+	"gin-app/internal/utils"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	config.InitDB()       // DB return করছে
-	config.ConnectRedis() // redis connection
+	flag.StringVar(&config.ConfigFile, "config", config.ConfigFile, "path to config.yaml (profiles config.<APP_ENV>.yaml are read from the same folder)")
+	flag.Parse()
+
+	config.LoadConfig()
+	config.InitLogger()
+	shutdownTracing := config.InitTracing()
+	if config.IsProduction() {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// Background jobs run until shutdown
+	background, stopBackground := context.WithCancel(context.Background())
+
+	config.InitDB()               // DB return করছে
+	config.ConnectRedis()         // redis connection
+	utils.InitJWTKeys(background) // signing keys + scheduled rotation
 	r := router.SetupRouter()
-	r.Run(":8080")
+
+	err := serve(r)
+
+	// Connections are closed only after in-flight requests are done
+	stopBackground()
+	config.CloseRedis()
+	config.CloseDB()
+	if err := shutdownTracing(context.Background()); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+
+	if err != nil {
+		slog.Error("Server failed", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Server stopped")
+}
+
+// serve runs the HTTP server (and the metrics listener of metrics.addr) until
+// SIGINT/SIGTERM, then drains in-flight requests for up to server.shutdown_timeout
+func serve(handler http.Handler) error {
+	conf := config.AppConfig.Server
+	servers := []*http.Server{newServer(conf.Addr, handler)}
+	if m := config.AppConfig.Metrics; m.Enabled && m.Addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		servers = append(servers, newServer(m.Addr, mux))
+	}
+
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	serveErr := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			slog.Info("Listening", "addr", srv.Addr, "env", config.Env)
+			serveErr <- srv.ListenAndServe()
+		}()
+	}
+
+	var err error
+	select {
+	case err = <-serveErr: // failed to listen
+	case <-signals.Done():
+		stopSignals() // a second signal kills the process right away
+		slog.Info("Shutting down, draining requests", "timeout", conf.ShutdownTimeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), conf.ShutdownTimeout)
+	defer cancel()
+	for _, srv := range servers {
+		if shutdownErr := srv.Shutdown(ctx); shutdownErr != nil && err == nil {
+			err = fmt.Errorf("graceful shutdown timed out, in-flight requests were cut: %w", shutdownErr)
+		}
+	}
+	return err
+}
+
+// newServer applies the timeouts of the server section
+func newServer(addr string, handler http.Handler) *http.Server {
+	conf := config.AppConfig.Server
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       conf.ReadTimeout,
+		ReadHeaderTimeout: conf.ReadHeaderTimeout,
+		WriteTimeout:      conf.WriteTimeout,
+		IdleTimeout:       conf.IdleTimeout,
+	}
 }
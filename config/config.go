@@ -2,18 +2,79 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"gin-app/internal/pkg/logger"
+	"io"
 	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	App struct {
-		Name      string
-		JwtSecret string `mapstructure:"jwt_secret"`
-		TokenTTL  string `mapstructure:"token_ttl"`
+		Name        string
+		TokenTTL    time.Duration `mapstructure:"token_ttl"`    // access tokens (admin + API)
+		RefreshTTL  time.Duration `mapstructure:"refresh_ttl"`  // refresh tokens
+		RememberTTL time.Duration `mapstructure:"remember_ttl"` // admin refresh token with "remember me"
+		SessionTTL  time.Duration `mapstructure:"session_ttl"`  // admin web session (flash messages)
+		URL         string        `mapstructure:"url"`
 	} `mapstructure:"app"`
 
+	Log struct {
+		Level  string // debug, info, warn or error
+		Format string // text or json
+	} `mapstructure:"log"`
+
+	// Prometheus /metrics
+	Metrics struct {
+		Enabled bool
+		Addr    string // separate listener, e.g. ":9090"; empty = /metrics on the main server
+		Token   string // bearer token required for /metrics on the main server
+	} `mapstructure:"metrics"`
+
+	// OpenTelemetry spans of requests, SQL queries and Redis commands
+	Tracing struct {
+		Enabled     bool
+		ServiceName string  `mapstructure:"service_name"` // defaults to app.name
+		Exporter    string  // otlp, stdout or file
+		Endpoint    string  // OTLP/HTTP receiver host:port, e.g. "localhost:4318"
+		Insecure    bool    // OTLP over plain HTTP
+		File        string  // file exporter output
+		SampleRate  float64 `mapstructure:"sample_rate"` // 0..1
+	} `mapstructure:"tracing"`
+
+	// HTTP server of cmd/main.go
+	Server struct {
+		Addr              string        // listen address, e.g. ":8080"
+		ReadTimeout       time.Duration `mapstructure:"read_timeout"`        // whole request incl. body
+		ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"` // request headers (slowloris)
+		WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+		IdleTimeout       time.Duration `mapstructure:"idle_timeout"`     // keep-alive connections
+		ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"` // draining of in-flight requests on SIGINT/SIGTERM
+	} `mapstructure:"server"`
+
+	Jwt struct {
+		Algorithm        string        // RS256 or EdDSA
+		Issuer           string        // iss claim, defaults to app.url
+		KeysDir          string        `mapstructure:"keys_dir"`
+		RotationInterval time.Duration `mapstructure:"rotation_interval"` // age of the signing key before a new one is generated
+		GracePeriod      time.Duration `mapstructure:"grace_period"`      // replaced keys keep verifying this long, must outlive the longest token
+	} `mapstructure:"jwt"`
+
+	// Attributes of the admin cookies (auth, CSRF, 2FA)
+	Cookie struct {
+		Secure   bool   // HTTPS only; required for same_site "none"
+		SameSite string `mapstructure:"same_site"` // lax, strict or none
+		Domain   string // empty = host of the request
+	} `mapstructure:"cookie"`
+
 	Database struct {
 		Host     string
 		Port     int
@@ -21,36 +82,331 @@ type Config struct {
 		Password string
 		Name     string
 		SSLMode  string
+
+		MaxOpenConns    int           `mapstructure:"max_open_conns"` // 0 = unlimited
+		MaxIdleConns    int           `mapstructure:"max_idle_conns"`
+		ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"` // 0 = reuse forever
+		ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+
+		ConnectRetries int `mapstructure:"connect_retries"` // ping attempts on startup
+
+		SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"` // 0 = don't log slow queries
+
+		MigrationsDir string `mapstructure:"migrations_dir"` // goose migrations, also checked by /readyz
 	} `mapstructure:"database"`
 
 	Redis struct {
-		Host     string
-		Port     int
-		Password string
-		DB       int
+		Mode       string   // single (default), sentinel or cluster
+		Addr       string   // host:port in single mode
+		Addrs      []string // sentinel or cluster nodes
+		MasterName string   `mapstructure:"master_name"` // sentinel
+		Username   string
+		Password   string
+		DB         int
+
+		SentinelUsername string `mapstructure:"sentinel_username"`
+		SentinelPassword string `mapstructure:"sentinel_password"`
+
+		TLS struct {
+			Enabled            bool
+			ServerName         string `mapstructure:"server_name"`
+			InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"` // never in production
+		} `mapstructure:"tls"`
+
+		PoolSize     int           `mapstructure:"pool_size"` // 0 = 10 per CPU
+		MinIdleConns int           `mapstructure:"min_idle_conns"`
+		DialTimeout  time.Duration `mapstructure:"dial_timeout"`
+		ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+		WriteTimeout time.Duration `mapstructure:"write_timeout"`
+
+		ConnectRetries int `mapstructure:"connect_retries"` // PING attempts on startup
 	} `mapstructure:"redis"`
+
+	Mail struct {
+		Driver  string // "log" writes mails to LogPath (or stdout) instead of sending
+		From    string
+		LogPath string `mapstructure:"log_path"`
+	} `mapstructure:"mail"`
 }
 
 var AppConfig Config
 
+// Environment and config file selection
+var (
+	// Env is the profile from $APP_ENV ("dev" when unset); config.<env>.yaml is merged over config.yaml
+	Env = "dev"
+
+	// ConfigFile is the base config file, set by the --config flag or $APP_CONFIG
+	ConfigFile = "./config/config.yaml"
+)
+
+// Secrets that must never reach production
+var defaultSecrets = map[string]bool{"": true, "password": true, "postgres": true, "secret": true, "changeme": true}
+
+// placeholderHosts are sample app.url hosts that must not reach production
+var placeholderHosts = map[string]bool{"example.com": true, "www.example.com": true, "localhost": true, "127.0.0.1": true}
+
+// LoadConfig loads the config and exits when it is missing or invalid
 func LoadConfig() {
-	viper.SetConfigName("config") // config.yaml
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("./config") // config folder
+	if err := Load(); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	log.Printf("✅ Config loaded successfully (env %s)", Env)
+}
 
-	err := viper.ReadInConfig()
-	if err != nil {
-		log.Fatalf("❌ Failed to read config: %v", err)
+// Load reads config.yaml, merges the profile of $APP_ENV and applies APP_*
+// environment variables, e.g. APP_DATABASE_PASSWORD for database.password
+func Load() error {
+	if env := strings.TrimSpace(os.Getenv("APP_ENV")); env != "" {
+		Env = strings.ToLower(env)
+	}
+	if path := os.Getenv("APP_CONFIG"); path != "" && ConfigFile == "./config/config.yaml" {
+		ConfigFile = path
+	}
+
+	viper.SetDefault("app.token_ttl", "15m")
+	viper.SetDefault("app.refresh_ttl", "7d")
+	viper.SetDefault("app.remember_ttl", "30d")
+	viper.SetDefault("app.session_ttl", "2h")
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "text")
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("tracing.exporter", "otlp")
+	viper.SetDefault("tracing.sample_rate", 1.0)
+	viper.SetDefault("server.addr", ":8080")
+	viper.SetDefault("server.read_timeout", "15s")
+	viper.SetDefault("server.read_header_timeout", "5s")
+	viper.SetDefault("server.write_timeout", "30s")
+	viper.SetDefault("server.idle_timeout", "60s")
+	viper.SetDefault("server.shutdown_timeout", "20s")
+	viper.SetDefault("jwt.rotation_interval", "30d")
+	viper.SetDefault("jwt.grace_period", "31d")
+	viper.SetDefault("cookie.same_site", "lax")
+	viper.SetDefault("database.max_open_conns", 25)
+	viper.SetDefault("database.max_idle_conns", 25)
+	viper.SetDefault("database.conn_max_lifetime", "30m")
+	viper.SetDefault("database.conn_max_idle_time", "5m")
+	viper.SetDefault("database.connect_retries", 10)
+	viper.SetDefault("database.migrations_dir", "./migrations")
+	viper.SetDefault("redis.mode", "single")
+	viper.SetDefault("redis.addr", "127.0.0.1:6379")
+	viper.SetDefault("redis.dial_timeout", "5s")
+	viper.SetDefault("redis.read_timeout", "3s")
+	viper.SetDefault("redis.write_timeout", "3s")
+	viper.SetDefault("redis.connect_retries", 5)
+
+	viper.SetConfigFile(ConfigFile)
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config %s: %w", ConfigFile, err)
+	}
+
+	// config.prod.yaml next to config.yaml
+	ext := filepath.Ext(ConfigFile)
+	profile := strings.TrimSuffix(ConfigFile, ext) + "." + Env + ext
+	if _, err := os.Stat(profile); err == nil {
+		viper.SetConfigFile(profile)
+		if err := viper.MergeInConfig(); err != nil {
+			return fmt.Errorf("failed to read config %s: %w", profile, err)
+		}
+	} else if IsProduction() {
+		return fmt.Errorf("missing production config %s", profile)
+	}
+
+	viper.SetEnvPrefix("APP")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+	bindEnvs(reflect.TypeOf(Config{}), "")
+
+	if err := viper.Unmarshal(&AppConfig, viper.DecodeHook(decodeHook)); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	for _, validate := range []func() error{validateLog, validateTracing, validateServer, validateTokenLifetimes, validateCookie, validateDatabase, validateRedis, validateProduction} {
+		if err := validate(); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+	}
+	return nil
+}
+
+// IsProduction reports whether APP_ENV is prod/production
+func IsProduction() bool {
+	return Env == "prod" || Env == "production"
+}
+
+// bindEnvs registers every config key so APP_* variables apply even to keys
+// missing from the yaml files (viper's AutomaticEnv only sees known keys)
+func bindEnvs(t reflect.Type, prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" {
+			key = strings.ToLower(field.Name)
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeOf(time.Duration(0)) {
+			bindEnvs(field.Type, key)
+			continue
+		}
+		_ = viper.BindEnv(key)
+	}
+}
+
+// validateProduction refuses to start production with development secrets or insecure cookies
+func validateProduction() error {
+	if !IsProduction() {
+		return nil
 	}
 
-	err = viper.Unmarshal(&AppConfig)
+	switch {
+	case defaultSecrets[AppConfig.Database.Password]:
+		return errors.New("database.password is empty or a default value, set APP_DATABASE_PASSWORD")
+	case AppConfig.Redis.Password != "" && defaultSecrets[AppConfig.Redis.Password]:
+		return errors.New("redis.password is a default value, set APP_REDIS_PASSWORD")
+	case !AppConfig.Cookie.Secure:
+		return errors.New("cookie.secure must be true in production")
+	case !strings.HasPrefix(AppConfig.App.URL, "https://"):
+		return errors.New("app.url must be an https:// URL in production, set APP_APP_URL")
+	case placeholderHosts[appURLHost()]:
+		return errors.New("app.url is a placeholder, set APP_APP_URL to the public URL")
+	case AppConfig.Redis.TLS.InsecureSkipVerify:
+		return errors.New("redis.tls.insecure_skip_verify must be false in production")
+	case AppConfig.Metrics.Enabled && AppConfig.Metrics.Addr == "" && defaultSecrets[AppConfig.Metrics.Token]:
+		return errors.New("metrics.token must be set (APP_METRICS_TOKEN) or metrics.addr used in production")
+	}
+	return nil
+}
+
+// appURLHost returns the lower-cased host of app.url without the port
+func appURLHost() string {
+	u, err := url.Parse(AppConfig.App.URL)
 	if err != nil {
-		log.Fatalf("❌ Failed to unmarshal config: %v", err)
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// validateTokenLifetimes rejects TTLs that would log users out early or let
+// tokens outlive the key that signed them
+func validateTokenLifetimes() error {
+	app, jwt := AppConfig.App, AppConfig.Jwt
+
+	switch {
+	case app.TokenTTL <= 0:
+		return errors.New("app.token_ttl must be positive")
+	case app.RefreshTTL < app.TokenTTL:
+		return errors.New("app.refresh_ttl must not be shorter than app.token_ttl")
+	case app.RememberTTL < app.RefreshTTL:
+		return errors.New("app.remember_ttl must not be shorter than app.refresh_ttl")
+	case app.SessionTTL <= 0:
+		return errors.New("app.session_ttl must be positive")
+	case jwt.RotationInterval < 0:
+		return errors.New("jwt.rotation_interval must not be negative")
+	case jwt.GracePeriod < app.RememberTTL:
+		return errors.New("jwt.grace_period must not be shorter than app.remember_ttl, tokens would outlive their key")
+	}
+	return nil
+}
+
+func validateLog() error {
+	_, err := logger.New(io.Discard, AppConfig.Log.Level, AppConfig.Log.Format)
+	return err
+}
+
+func validateTracing() error {
+	t := AppConfig.Tracing
+	if !t.Enabled {
+		return nil
+	}
+
+	switch {
+	case t.Exporter != "otlp" && t.Exporter != "stdout" && t.Exporter != "file":
+		return fmt.Errorf("tracing.exporter must be otlp, stdout or file, got %q", t.Exporter)
+	case t.Exporter == "file" && t.File == "":
+		return errors.New("tracing.file is required for the file exporter")
+	case t.SampleRate < 0 || t.SampleRate > 1:
+		return errors.New("tracing.sample_rate must be between 0 and 1")
+	}
+	return nil
+}
+
+func validateServer() error {
+	srv := AppConfig.Server
+
+	switch {
+	case srv.Addr == "":
+		return errors.New("server.addr is required")
+	case srv.ReadTimeout < 0 || srv.ReadHeaderTimeout < 0 || srv.WriteTimeout < 0 || srv.IdleTimeout < 0:
+		return errors.New("server timeouts must not be negative")
+	case srv.ShutdownTimeout <= 0:
+		return errors.New("server.shutdown_timeout must be positive")
+	case AppConfig.Metrics.Enabled && AppConfig.Metrics.Addr == srv.Addr:
+		return errors.New("metrics.addr must differ from server.addr, leave it empty to serve /metrics on the main server")
 	}
+	return nil
+}
 
-	// Checking if JWT_SECRET is set
-	// jwtKey := []byte(viper.GetString("app.jwt_secret"))
-	// log.Println("✅ JWT Key:", string(jwtKey))
+func validateCookie() error {
+	cookie := AppConfig.Cookie
 
-	log.Println("✅ Config loaded successfully")
+	switch strings.ToLower(cookie.SameSite) {
+	case "lax", "strict":
+	case "none":
+		if !cookie.Secure {
+			return errors.New("cookie.same_site \"none\" requires cookie.secure")
+		}
+	default:
+		return errors.New("cookie.same_site must be lax, strict or none")
+	}
+	return nil
+}
+
+func validateDatabase() error {
+	db := AppConfig.Database
+
+	switch {
+	case db.MaxOpenConns < 0 || db.MaxIdleConns < 0:
+		return errors.New("database.max_open_conns and database.max_idle_conns must not be negative")
+	case db.MaxOpenConns > 0 && db.MaxIdleConns > db.MaxOpenConns:
+		return errors.New("database.max_idle_conns must not exceed database.max_open_conns")
+	case db.ConnMaxLifetime < 0 || db.ConnMaxIdleTime < 0:
+		return errors.New("database.conn_max_lifetime and database.conn_max_idle_time must not be negative")
+	case db.ConnectRetries < 1:
+		return errors.New("database.connect_retries must be at least 1")
+	case db.SlowQueryThreshold < 0:
+		return errors.New("database.slow_query_threshold must not be negative")
+	}
+	return nil
+}
+
+func validateRedis() error {
+	r := AppConfig.Redis
+
+	switch strings.ToLower(r.Mode) {
+	case "", "single":
+		if r.Addr == "" {
+			return errors.New("redis.addr is required")
+		}
+	case "sentinel":
+		if len(r.Addrs) == 0 || r.MasterName == "" {
+			return errors.New("redis.addrs (sentinels) and redis.master_name are required in sentinel mode")
+		}
+	case "cluster":
+		if len(r.Addrs) == 0 {
+			return errors.New("redis.addrs is required in cluster mode")
+		}
+		if r.DB != 0 {
+			return errors.New("redis.db must be 0 in cluster mode")
+		}
+	default:
+		return fmt.Errorf("redis.mode must be single, sentinel or cluster, got %q", r.Mode)
+	}
+
+	if r.ConnectRetries < 1 {
+		return errors.New("redis.connect_retries must be at least 1")
+	}
+	return nil
 }
@@ -2,13 +2,19 @@
 package config
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
-	"log"
+	"gin-app/internal/pkg/metrics"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
 
 	_ "github.com/lib/pq" // PostgreSQL driver
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/extra/bunotel"
 )
 
 var DB *bun.DB
@@ -25,15 +31,16 @@ func GetDSN() string {
 		sslmode = "disable" // default fallback
 	}
 
-	return fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		dbConf.User,
-		dbConf.Password,
-		dbConf.Host,
-		dbConf.Port,
-		dbConf.Name,
-		sslmode,
-	)
+	// url.URL escapes user, password and database name, so secrets with
+	// @, /, : or ? keep working
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(dbConf.User, dbConf.Password),
+		Host:     net.JoinHostPort(dbConf.Host, strconv.Itoa(dbConf.Port)),
+		Path:     "/" + dbConf.Name,
+		RawQuery: url.Values{"sslmode": {sslmode}}.Encode(),
+	}
+	return dsn.String()
 }
 
 // InitDB initializes Bun with PostgreSQL
@@ -43,16 +50,53 @@ func InitDB() {
 	// Open underlying *sql.DB
 	sqlDB, err := sql.Open("postgres", dsn)
 	if err != nil {
-		log.Fatalf("❌ Failed to open DB: %v", err)
+		slog.Error("Failed to open DB", "error", err)
+		os.Exit(1)
 	}
 
-	// Test connectivity
-	if err := sqlDB.Ping(); err != nil {
-		log.Fatalf("❌ Failed to ping DB: %v", err)
+	conf := AppConfig.Database
+	sqlDB.SetMaxOpenConns(conf.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(conf.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(conf.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(conf.ConnMaxIdleTime)
+
+	// Test connectivity, waiting for Postgres if it is still starting
+	if err := PingDB(Ctx, sqlDB, conf.ConnectRetries); err != nil {
+		slog.Error("Failed to connect to PostgreSQL", "host", conf.Host, "port", conf.Port, "error", err)
+		os.Exit(1)
 	}
 
 	// Assign Bun DB globally
 	DB = bun.NewDB(sqlDB, pgdialect.New())
+	DB.AddQueryHook(&QueryHook{SlowThreshold: conf.SlowQueryThreshold})
+	if AppConfig.Tracing.Enabled {
+		DB.AddQueryHook(bunotel.NewQueryHook(bunotel.WithDBName(conf.Name)))
+	}
+	if AppConfig.Metrics.Enabled {
+		DB.AddQueryHook(metrics.QueryHook{})
+		if err := metrics.RegisterDB(sqlDB, conf.Name); err != nil {
+			slog.Warn("Failed to register DB pool metrics", "error", err)
+		}
+	}
+
+	slog.Info("Connected to PostgreSQL", "host", conf.Host, "database", conf.Name)
+}
+
+// PingDB pings the database up to attempts times with backoff
+func PingDB(ctx context.Context, sqlDB *sql.DB, attempts int) error {
+	return retryConnect(ctx, "PostgreSQL", attempts, func(ctx context.Context) error {
+		return sqlDB.PingContext(ctx)
+	})
+}
 
-	log.Println("✅ Connected to PostgreSQL (Bun)")
+// CloseDB closes the connection pool on shutdown
+func CloseDB() {
+	if DB == nil {
+		return
+	}
+	if err := DB.Close(); err != nil {
+		slog.Error("Failed to close DB", "error", err)
+		return
+	}
+	slog.Info("PostgreSQL connection closed")
 }
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration is time.ParseDuration with day ("d") and week ("w") units,
+// e.g. "30d", "1w", "1d12h", "15m". A bare number is seconds ("900"), the
+// same as a number in YAML. Negative durations are rejected.
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	if strings.Trim(s, "0123456789.") == "" {
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n * float64(time.Second)), nil
+	}
+
+	var total time.Duration
+	rest := s
+	for rest != "" {
+		// leading number
+		i := 0
+		for i < len(rest) && (rest[i] >= '0' && rest[i] <= '9' || rest[i] == '.') {
+			i++
+		}
+		j := i
+		for j < len(rest) && (rest[j] < '0' || rest[j] > '9') && rest[j] != '.' {
+			j++
+		}
+		if i == 0 || i == j {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+
+		num, unit := rest[:i], rest[i:j]
+		rest = rest[j:]
+
+		var per time.Duration
+		switch unit {
+		case "d":
+			per = 24 * time.Hour
+		case "w":
+			per = 7 * 24 * time.Hour
+		default:
+			d, err := time.ParseDuration(num + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			total += d
+			continue
+		}
+
+		n, err := strconv.ParseFloat(num, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(n * float64(per))
+	}
+	return total, nil
+}
+
+// decodeHook lets time.Duration fields be written as "30d" and list fields as
+// "a,b" (the form they have in environment variables)
+func decodeHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	switch to {
+	case reflect.TypeOf(time.Duration(0)):
+		// Plain numbers are seconds, whether YAML decoded them or they came
+		// as a string from an environment variable
+		switch v := data.(type) {
+		case string:
+			return ParseDuration(v)
+		case int:
+			return secondsDuration(float64(v))
+		case int64:
+			return secondsDuration(float64(v))
+		case uint64:
+			return secondsDuration(float64(v))
+		case float64:
+			return secondsDuration(v)
+		}
+	case reflect.TypeOf([]string{}):
+		if v, ok := data.(string); ok {
+			if strings.TrimSpace(v) == "" {
+				return []string{}, nil
+			}
+			parts := strings.Split(v, ",")
+			for i := range parts {
+				parts[i] = strings.TrimSpace(parts[i])
+			}
+			return parts, nil
+		}
+	}
+	return data, nil
+}
+
+func secondsDuration(n float64) (time.Duration, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("invalid duration %v: must not be negative", n)
+	}
+	return time.Duration(n * float64(time.Second)), nil
+}
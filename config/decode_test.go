@@ -0,0 +1,86 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "30d", want: 30 * 24 * time.Hour},
+		{in: "1.5d", want: 36 * time.Hour},
+		{in: "1w2d", want: 9 * 24 * time.Hour},
+		{in: "1h30m", want: 90 * time.Minute},
+		{in: "1d12h", want: 36 * time.Hour},
+		{in: "15m", want: 15 * time.Minute},
+		{in: "900", want: 900 * time.Second},
+		{in: " 900 ", want: 900 * time.Second},
+		{in: "0", want: 0},
+		{in: "", wantErr: true},
+		{in: "  ", wantErr: true},
+		{in: "-5m", wantErr: true},
+		{in: "-900", wantErr: true},
+		{in: "1x", wantErr: true},
+		{in: "d", wantErr: true},
+		{in: ".", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeHookDuration(t *testing.T) {
+	durationType := reflect.TypeOf(time.Duration(0))
+
+	// YAML numbers and environment variable strings mean the same
+	tests := []struct {
+		in      interface{}
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "900", want: 900 * time.Second},
+		{in: 900, want: 900 * time.Second},
+		{in: int64(900), want: 900 * time.Second},
+		{in: uint64(900), want: 900 * time.Second},
+		{in: 1.5, want: 1500 * time.Millisecond},
+		{in: "7d", want: 7 * 24 * time.Hour},
+		{in: -900, wantErr: true},
+		{in: "-900", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := decodeHook(reflect.TypeOf(tt.in), durationType, tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("decodeHook(%#v) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("decodeHook(%#v) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("decodeHook(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package config
+
+import (
+	"gin-app/internal/pkg/logger"
+	"log"
+	"log/slog"
+	"os"
+)
+
+// InitLogger installs the slog logger of the log section as default; the
+// standard log package writes through it too
+func InitLogger() {
+	l, err := logger.New(os.Stdout, AppConfig.Log.Level, AppConfig.Log.Format)
+	if err != nil {
+		log.Fatalf("❌ Invalid log config: %v", err)
+	}
+	slog.SetDefault(l)
+}
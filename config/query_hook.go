@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Long queries (bulk inserts) are cut in the log
+const slowQueryMaxLen = 2000
+
+// QueryHook logs every query at debug level and queries that take at least
+// SlowThreshold as warnings, with duration and the request ID of the context.
+// Bound values are never logged: model queries come formatted, so their
+// string literals (password hashes, 2FA secrets, ...) are replaced by '?'.
+type QueryHook struct {
+	SlowThreshold time.Duration // 0 = no slow query warnings
+}
+
+var _ bun.QueryHook = (*QueryHook)(nil)
+
+func (h *QueryHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	elapsed := time.Since(event.StartTime)
+
+	level, msg := slog.LevelDebug, "SQL query"
+	switch {
+	case h.SlowThreshold > 0 && elapsed >= h.SlowThreshold:
+		level, msg = slog.LevelWarn, "Slow SQL query"
+	case event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows):
+		level = slog.LevelWarn
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	// Raw queries keep their placeholders; model queries are already formatted
+	query := event.Query
+	if event.QueryTemplate != "" {
+		query = event.QueryTemplate
+	}
+	query = maskQueryLiterals(query)
+	if len(query) > slowQueryMaxLen {
+		query = query[:slowQueryMaxLen] + "..."
+	}
+
+	attrs := []any{
+		slog.String("operation", event.Operation()),
+		slog.Duration("duration", elapsed),
+		slog.String("query", query),
+	}
+	if len(event.QueryArgs) > 0 {
+		attrs = append(attrs, slog.Int("args", len(event.QueryArgs)))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.Any("error", event.Err))
+	}
+	slog.Log(ctx, level, msg, attrs...)
+}
+
+// maskQueryLiterals replaces every quoted string literal, doubled quotes
+// included, with '?'
+func maskQueryLiterals(query string) string {
+	if !strings.Contains(query, "'") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query))
+	for i := 0; i < len(query); i++ {
+		if query[i] != '\'' {
+			b.WriteByte(query[i])
+			continue
+		}
+		// Skip to the closing quote
+		for i++; i < len(query); i++ {
+			if query[i] == '\'' {
+				if i+1 < len(query) && query[i+1] == '\'' {
+					i++
+					continue
+				}
+				break
+			}
+		}
+		b.WriteString("'?'")
+	}
+	return b.String()
+}
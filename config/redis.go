@@ -4,22 +4,137 @@ package config
 
 import (
 	"context"
-	"log"
+	"crypto/tls"
+	"fmt"
+	"gin-app/internal/pkg/metrics"
+	"log/slog"
+	"os"
+	"strings"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
 var (
-	Ctx         = context.Background()
-	RedisClient *redis.Client
+	Ctx = context.Background()
+
+	// RedisClient is a *redis.Client, a failover (sentinel) client or a
+	// *redis.ClusterClient depending on redis.mode
+	RedisClient redis.UniversalClient
 )
 
+// ConnectRedis creates the client of the redis section and waits until Redis answers a PING
 func ConnectRedis() {
+	client, err := NewRedisClient()
+	if err != nil {
+		slog.Error("Invalid Redis config", "error", err)
+		os.Exit(1)
+	}
+
+	if err := PingRedis(Ctx, client, AppConfig.Redis.ConnectRetries); err != nil {
+		slog.Error("Failed to connect to Redis", "target", redisTarget(), "error", err)
+		os.Exit(1)
+	}
+
+	client.AddHook(RedisLogHook{})
+	if AppConfig.Metrics.Enabled {
+		client.AddHook(metrics.RedisHook{})
+	}
+	if AppConfig.Tracing.Enabled {
+		if err := redisotel.InstrumentTracing(client); err != nil {
+			slog.Warn("Failed to instrument Redis tracing", "error", err)
+		}
+	}
+	RedisClient = client
+	slog.Info("Connected to Redis", "target", redisTarget())
+}
+
+// CloseRedis closes the client and its pool on shutdown
+func CloseRedis() {
+	if RedisClient == nil {
+		return
+	}
+	if err := RedisClient.Close(); err != nil {
+		slog.Error("Failed to close Redis", "error", err)
+		return
+	}
+	slog.Info("Redis connection closed")
+}
 
-	RedisClient = redis.NewClient(&redis.Options{
-		Addr:     AppConfig.Redis.Host,
-		Password: AppConfig.Redis.Password,
-		DB:       AppConfig.Redis.DB,
+// NewRedisClient builds a client for redis.mode (single, sentinel or cluster) without connecting
+func NewRedisClient() (redis.UniversalClient, error) {
+	conf := AppConfig.Redis
+
+	var tlsConfig *tls.Config
+	if conf.TLS.Enabled {
+		tlsConfig = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ServerName:         conf.TLS.ServerName,
+			InsecureSkipVerify: conf.TLS.InsecureSkipVerify,
+		}
+	}
+
+	switch strings.ToLower(conf.Mode) {
+	case "", "single":
+		return redis.NewClient(&redis.Options{
+			Addr:         conf.Addr,
+			Username:     conf.Username,
+			Password:     conf.Password,
+			DB:           conf.DB,
+			TLSConfig:    tlsConfig,
+			PoolSize:     conf.PoolSize,
+			MinIdleConns: conf.MinIdleConns,
+			DialTimeout:  conf.DialTimeout,
+			ReadTimeout:  conf.ReadTimeout,
+			WriteTimeout: conf.WriteTimeout,
+		}), nil
+	case "sentinel":
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       conf.MasterName,
+			SentinelAddrs:    conf.Addrs,
+			SentinelUsername: conf.SentinelUsername,
+			SentinelPassword: conf.SentinelPassword,
+			Username:         conf.Username,
+			Password:         conf.Password,
+			DB:               conf.DB,
+			TLSConfig:        tlsConfig,
+			PoolSize:         conf.PoolSize,
+			MinIdleConns:     conf.MinIdleConns,
+			DialTimeout:      conf.DialTimeout,
+			ReadTimeout:      conf.ReadTimeout,
+			WriteTimeout:     conf.WriteTimeout,
+		}), nil
+	case "cluster":
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        conf.Addrs,
+			Username:     conf.Username,
+			Password:     conf.Password,
+			TLSConfig:    tlsConfig,
+			PoolSize:     conf.PoolSize,
+			MinIdleConns: conf.MinIdleConns,
+			DialTimeout:  conf.DialTimeout,
+			ReadTimeout:  conf.ReadTimeout,
+			WriteTimeout: conf.WriteTimeout,
+		}), nil
+	}
+	return nil, fmt.Errorf("unknown redis.mode %q", conf.Mode)
+}
+
+// PingRedis tries PING up to attempts times with backoff
+func PingRedis(ctx context.Context, client redis.UniversalClient, attempts int) error {
+	return retryConnect(ctx, "Redis", attempts, func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
 	})
-	log.Println("✅ Connected to Redis")
+}
+
+// redisTarget describes the configured server(s) for logs, without credentials
+func redisTarget() string {
+	conf := AppConfig.Redis
+	switch strings.ToLower(conf.Mode) {
+	case "sentinel":
+		return fmt.Sprintf("sentinel %s via %s", conf.MasterName, strings.Join(conf.Addrs, ","))
+	case "cluster":
+		return "cluster " + strings.Join(conf.Addrs, ",")
+	}
+	return fmt.Sprintf("%s db %d", conf.Addr, conf.DB)
 }
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisLogHook logs Redis commands at debug level and failed ones as warnings,
+// with the request ID of the context. Values are never logged and keys only
+// up to their last ":", because keys like admin_access:<token> hold secrets.
+type RedisLogHook struct{}
+
+var _ redis.Hook = RedisLogHook{}
+
+func (RedisLogHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisLogHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		logRedis(ctx, "Redis command", time.Since(start), err, slog.String("command", describeRedisCmd(cmd)))
+		return err
+	}
+}
+
+func (RedisLogHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+
+		names := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			names[i] = describeRedisCmd(cmd)
+		}
+		logRedis(ctx, "Redis pipeline", time.Since(start), err, slog.Any("commands", names))
+		return err
+	}
+}
+
+func logRedis(ctx context.Context, msg string, elapsed time.Duration, err error, attr slog.Attr) {
+	level := slog.LevelDebug
+	if err != nil && !errors.Is(err, redis.Nil) {
+		level = slog.LevelWarn
+	}
+	if !slog.Default().Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []any{attr, slog.Duration("duration", elapsed)}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	slog.Log(ctx, level, msg, attrs...)
+}
+
+// describeRedisCmd returns the command name and its masked key, e.g. "get admin_access:*"
+func describeRedisCmd(cmd redis.Cmder) string {
+	args := cmd.Args()
+	name := cmd.Name()
+	if len(args) < 2 || name == "auth" || name == "hello" {
+		return name
+	}
+
+	key, ok := args[1].(string)
+	if !ok {
+		return name
+	}
+	if i := strings.LastIndex(key, ":"); i >= 0 {
+		key = key[:i+1] + "*"
+	} else {
+		key = "*"
+	}
+	return name + " " + key
+}
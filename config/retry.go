@@ -0,0 +1,39 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Startup connection backoff: 500ms, 1s, 2s, 4s, 5s, 5s, ...
+const (
+	connectRetryDelay    = 500 * time.Millisecond
+	connectMaxRetryDelay = 5 * time.Second
+)
+
+// retryConnect calls fn up to attempts times, doubling the wait between tries,
+// so the app survives dependencies that start later (e.g. in docker-compose)
+func retryConnect(ctx context.Context, name string, attempts int, fn func(context.Context) error) error {
+	delay := connectRetryDelay
+
+	var err error
+	for i := 1; i <= attempts; i++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if i == attempts {
+			break
+		}
+
+		slog.Warn(name+" not reachable, retrying", "attempt", i, "attempts", attempts, "retry_in", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, connectMaxRetryDelay)
+	}
+	return fmt.Errorf("no reply after %d attempts: %w", attempts, err)
+}
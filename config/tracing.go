@@ -0,0 +1,42 @@
+package config
+
+import (
+	"context"
+	"gin-app/internal/pkg/tracing"
+	"log/slog"
+	"os"
+)
+
+// InitTracing installs the tracer provider of the tracing section. The
+// returned function flushes the pending spans on shutdown.
+func InitTracing() func(context.Context) error {
+	conf := AppConfig.Tracing
+	if !conf.Enabled {
+		return func(context.Context) error { return nil }
+	}
+
+	shutdown, err := tracing.Setup(Ctx, tracing.Options{
+		ServiceName: TracingServiceName(),
+		Environment: Env,
+		Exporter:    conf.Exporter,
+		Endpoint:    conf.Endpoint,
+		Insecure:    conf.Insecure,
+		File:        conf.File,
+		SampleRate:  conf.SampleRate,
+	})
+	if err != nil {
+		slog.Error("Failed to set up tracing", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Tracing enabled", "exporter", conf.Exporter, "sample_rate", conf.SampleRate)
+	return shutdown
+}
+
+// TracingServiceName is tracing.service_name, or app.name when not set
+func TracingServiceName() string {
+	if AppConfig.Tracing.ServiceName != "" {
+		return AppConfig.Tracing.ServiceName
+	}
+	return AppConfig.App.Name
+}
@@ -12,7 +12,6 @@ import (
 )
 
 func AdminCategoryList(c *gin.Context) {
-	successMsg := c.Query("success")
 	// Filters
 	search := c.Query("search")
 	status := c.Query("status")
@@ -44,11 +43,7 @@ func AdminCategoryList(c *gin.Context) {
 	// Fetch
 	var categories []models.Category
 	if err := query.Scan(c, &categories); err != nil {
-		c.HTML(http.StatusInternalServerError, "category_list.html", gin.H{
-			"title": "Category List",
-			"error": "Failed to fetch categories: " + err.Error(),
-			"data":  []models.Category{},
-		})
+		utils.Fail(c, utils.Internal(err, "Failed to fetch categories"))
 		return
 	}
 
@@ -66,7 +61,7 @@ func AdminCategoryList(c *gin.Context) {
 		totalCount = 0
 	}
 	// Render
-	c.HTML(http.StatusOK, "category_list.html", gin.H{
+	utils.HTML(c, http.StatusOK, "category_list.html", gin.H{
 		"title":      "Category List",
 		"PageName":   "category_list",
 		"data":       categories,
@@ -78,16 +73,20 @@ func AdminCategoryList(c *gin.Context) {
 			"status":     status,
 			"created_at": createdAt,
 		},
-		"success": successMsg, // pass to template
 	})
 }
 
 // Create page
 func AdminCategoryCreate(c *gin.Context) {
-	c.HTML(http.StatusOK, "category_create.html", gin.H{
+	data := gin.H{
 		"title":    "Create Category",
 		"PageName": "category_create",
-	})
+	}
+	var input dto.CategoryStoreDTO
+	if utils.OldInput(c, &input) {
+		data["data"] = input // re-fill after a failed submit
+	}
+	utils.HTML(c, http.StatusOK, "category_create.html", data)
 }
 
 // Category Store
@@ -96,11 +95,8 @@ func AdminCategoryStore(c *gin.Context) {
 
 	//  Bind + Validate
 	if valid, errs := utils.ValidateStruct(c, &input); !valid {
-		c.HTML(http.StatusBadRequest, "category_create.html", gin.H{
-			"title":  "Create Category",
-			"errors": errs,
-			"data":   input, // old input re-fill
-		})
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/category-create")
 		return
 	}
 
@@ -121,21 +117,15 @@ func AdminCategoryStore(c *gin.Context) {
 
 	// Database value insert
 	if _, err := config.DB.NewInsert().Model(&category).Exec(c); err != nil {
-		c.HTML(http.StatusInternalServerError, "category_create.html", gin.H{
-			"title":  "Create Category",
-			"errors": map[string]string{"DB": "Failed to create category: " + err.Error()},
-			"data":   input,
-		})
+		utils.FlashFailure(c, err, "Failed to create category")
+		utils.FlashInput(c, nil)
+		c.Redirect(http.StatusSeeOther, "/admin/category-create")
 		return
 	}
 
-	// Success response → empty form + success msg
-	c.HTML(http.StatusOK, "category_create.html", gin.H{
-		"title":   "Create Category",
-		"success": "Category created successfully!",
-		"errors":  map[string]string{},
-		"data":    dto.CategoryStoreDTO{}, // clear form
-	})
+	// Empty form + success msg
+	utils.FlashSuccess(c, "Category created successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/category-create")
 }
 
 // Category Edit
@@ -145,13 +135,17 @@ func AdminEditCategory(c *gin.Context) {
 	// Fetch category by ID
 	var category models.Category
 	if err := config.DB.NewSelect().Model(&category).Where("id = ?", id).Scan(c); err != nil {
-		c.HTML(http.StatusNotFound, "404.html", gin.H{
-			"title": "Category Not Found",
-		})
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Category not found"))
 		return
 	}
 
-	c.HTML(http.StatusOK, "category_edit.html", gin.H{
+	// Re-fill the rejected input of a failed update
+	var input dto.CategoryUpdateDTO
+	if utils.OldInput(c, &input) {
+		category.Name, category.Status = input.Name, input.Status
+	}
+
+	utils.HTML(c, http.StatusOK, "category_edit.html", gin.H{
 		"title":    "Edit Category",
 		"PageName": "category_edit",
 		"data":     category,
@@ -163,14 +157,15 @@ func AdminUpdateCategory(c *gin.Context) {
 	id := c.Param("id")
 
 	var req dto.CategoryUpdateDTO
-	if err := c.ShouldBind(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if valid, errs := utils.ValidateStruct(c, &req); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/category-edit/"+id)
 		return
 	}
 
 	var category models.Category
 	if err := config.DB.NewSelect().Model(&category).Where("id = ?", id).Scan(c); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Category not found"))
 		return
 	}
 
@@ -181,11 +176,12 @@ func AdminUpdateCategory(c *gin.Context) {
 
 	_, err := config.DB.NewUpdate().Model(&category).Where("id = ?", id).Exec(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category"})
+		utils.Fail(c, utils.Internal(err, "Failed to update category"))
 		return
 	}
 
-	c.Redirect(http.StatusSeeOther, "/admin/category-list?success=Category+updated+successfully!")
+	utils.FlashSuccess(c, "Category updated successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/category-list")
 }
 
 // Category Deleted
@@ -195,14 +191,14 @@ func AdminDeleteCategory(c *gin.Context) {
 	// Check if category exists
 	var category models.Category
 	if err := config.DB.NewSelect().Model(&category).Where("id = ?", id).Scan(c); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Category not found"))
 		return
 	}
 
 	// Delete category
 	_, err := config.DB.NewDelete().Model(&category).Where("id = ?", id).Exec(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+		utils.Fail(c, utils.Internal(err, "Failed to delete category"))
 		return
 	}
 
@@ -214,14 +210,14 @@ func AdminToggleCategoryStatus(c *gin.Context) {
 
 	var category models.Category
 	if err := config.DB.NewSelect().Model(&category).Where("id = ?", id).Scan(c); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Category not found"))
 		return
 	}
 
 	category.Status = 1 - category.Status
 	_, err := config.DB.NewUpdate().Model(&category).Where("id = ?", id).Exec(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category status"})
+		utils.Fail(c, utils.Internal(err, "Failed to update category status"))
 		return
 	}
 
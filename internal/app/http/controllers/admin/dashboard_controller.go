@@ -1,41 +1,48 @@
 package controllers
 
 import (
-	"gin-app/config"
+	"errors"
 	"gin-app/internal/utils"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 func AdminDashboard(c *gin.Context) {
-	admin, _, err := utils.GetLoggedInAdmin(c)
-	if err != nil {
-		c.Redirect(303, "/admin/login")
-		return
-	}
-	c.HTML(http.StatusOK, "dashboard.html", gin.H{
+	admin := utils.CurrentAdmin(c)
+	utils.HTML(c, http.StatusOK, "dashboard.html", gin.H{
 		"admin": admin,
 		"title": "Dashboard",
 	})
 
 }
 
-// Admin logout
+// Admin logout (?everywhere=1 ends every admin and API session of the user)
 func AdminLogout(c *gin.Context) {
-	accessToken, _ := c.Cookie("admin_access")
-	refreshToken, _ := c.Cookie("admin_refresh")
-
-	if accessToken != "" {
-		config.RedisClient.Del(config.Ctx, "admin_access:"+accessToken)
+	admin := utils.CurrentAdmin(c)
+
+	// The session set by AdminAuthMiddleware: when the access token had expired
+	// it already rotated the pair, so the request cookies point to nothing
+	if sessionID := c.GetString(utils.AdminSessionContextKey); sessionID != "" {
+		if err := utils.RevokeSession(c.Request.Context(), utils.AdminScope, admin.ID, sessionID); err != nil && !errors.Is(err, utils.ErrSessionNotFound) {
+			slog.ErrorContext(c.Request.Context(), "Failed to revoke session", "user_id", admin.ID, "error", err)
+		}
+	} else {
+		// Tokens issued before sessions were indexed
+		accessToken, _ := c.Cookie("admin_access")
+		refreshToken, _ := c.Cookie("admin_refresh")
+		utils.RevokeTokens(c.Request.Context(), utils.AdminScope, accessToken, refreshToken)
 	}
-	if refreshToken != "" {
-		config.RedisClient.Del(config.Ctx, "admin_refresh:"+refreshToken)
+
+	if c.Query("everywhere") == "1" {
+		if err := utils.RevokeUserTokens(c.Request.Context(), admin.ID); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to revoke sessions", "user_id", admin.ID, "error", err)
+		}
 	}
 
 	// Clear cookies
-	c.SetCookie("admin_access", "", -1, "/", "", false, true)
-	c.SetCookie("admin_refresh", "", -1, "/", "", false, true)
+	utils.ClearAdminCookies(c)
 
 	c.Redirect(http.StatusSeeOther, "/admin/login")
 }
@@ -1,7 +1,6 @@
 package controllers
 
 import (
-	"context"
 	"gin-app/config"
 	"gin-app/internal/dto"
 	"gin-app/internal/models"
@@ -14,7 +13,6 @@ import (
 
 // Job Type function
 func AdminJobTypeList(c *gin.Context) {
-	successMsg := c.Query("success")
 	// Filters
 	search := c.Query("search")
 	status := c.Query("status")
@@ -46,11 +44,7 @@ func AdminJobTypeList(c *gin.Context) {
 	// Fetch
 	var jobs []models.JobType
 	if err := query.Scan(c, &jobs); err != nil {
-		c.HTML(http.StatusInternalServerError, "job_type_list.html", gin.H{
-			"title": "Job Type List",
-			"error": "Failed to fetch job types: " + err.Error(),
-			"data":  []models.JobType{},
-		})
+		utils.Fail(c, utils.Internal(err, "Failed to fetch job types"))
 		return
 	}
 
@@ -68,7 +62,7 @@ func AdminJobTypeList(c *gin.Context) {
 		totalCount = 0
 	}
 	// Render
-	c.HTML(http.StatusOK, "job_type_list.html", gin.H{
+	utils.HTML(c, http.StatusOK, "job_type_list.html", gin.H{
 		"title":      "Job Type List",
 		"PageName":   "job_type_list",
 		"data":       jobs,
@@ -80,16 +74,19 @@ func AdminJobTypeList(c *gin.Context) {
 			"status":     status,
 			"created_at": createdAt,
 		},
-		"success": successMsg, // pass to template
 	})
 }
 
 // Job type create page
 func AdminJobTypeCreate(c *gin.Context) {
-
-	c.HTML(http.StatusOK, "job_type_create.html", gin.H{
+	data := gin.H{
 		"title": "Job Type Create",
-	})
+	}
+	var input dto.JobTypeStoreDTO
+	if utils.OldInput(c, &input) {
+		data["data"] = input // re-fill after a failed submit
+	}
+	utils.HTML(c, http.StatusOK, "job_type_create.html", data)
 }
 
 // Jobtype store
@@ -98,11 +95,8 @@ func AdminJobTypeStore(c *gin.Context) {
 
 	//  Bind + Validate
 	if valid, errs := utils.ValidateStruct(c, &input); !valid {
-		c.HTML(http.StatusBadRequest, "job_type_create.html", gin.H{
-			"title":  "Create Job Type",
-			"errors": errs,
-			"data":   input, // old input re-fill
-		})
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/job-type-create")
 		return
 	}
 
@@ -123,21 +117,15 @@ func AdminJobTypeStore(c *gin.Context) {
 
 	// Database value insert
 	if _, err := config.DB.NewInsert().Model(&job).Exec(c); err != nil {
-		c.HTML(http.StatusInternalServerError, "job_type_create.html", gin.H{
-			"title":  "Create Job Type",
-			"errors": map[string]string{"DB": "Failed to create job type: " + err.Error()},
-			"data":   input,
-		})
+		utils.FlashFailure(c, err, "Failed to create job type")
+		utils.FlashInput(c, nil)
+		c.Redirect(http.StatusSeeOther, "/admin/job-type-create")
 		return
 	}
 
-	// Success response → empty form + success msg
-	c.HTML(http.StatusOK, "job_type_create.html", gin.H{
-		"title":   "Create Job Type",
-		"success": "Job Type created successfully!",
-		"errors":  map[string]string{},
-		"data":    dto.JobTypeStoreDTO{}, // clear form
-	})
+	// Empty form + success msg
+	utils.FlashSuccess(c, "Job Type created successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/job-type-create")
 }
 
 // Status job type update
@@ -148,7 +136,7 @@ func AdminToggleJobTypeStatus(c *gin.Context) {
 		Status int `json:"status"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		utils.Fail(c, utils.Validation("Invalid input", nil))
 		return
 	}
 
@@ -156,9 +144,9 @@ func AdminToggleJobTypeStatus(c *gin.Context) {
 		Model(&models.JobType{}).
 		Set("status = ?", req.Status).
 		Where("id = ?", id).
-		Exec(context.Background())
+		Exec(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update"})
+		utils.Fail(c, utils.Internal(err, "Failed to update job type status"))
 		return
 	}
 
@@ -173,9 +161,9 @@ func AdminDeleteJobType(c *gin.Context) {
 	_, err := config.DB.NewDelete().
 		Model(&models.JobType{}).
 		Where("id = ?", id).
-		Exec(context.Background())
+		Exec(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete"})
+		utils.Fail(c, utils.Internal(err, "Failed to delete job type"))
 		return
 	}
 
@@ -188,11 +176,17 @@ func AdminEditJobType(c *gin.Context) {
 
 	var job models.JobType
 	if err := config.DB.NewSelect().Model(&job).Where("id = ?", id).Scan(c); err != nil {
-		c.HTML(http.StatusNotFound, "404.html", gin.H{"title": "Not Found"})
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Job type not found"))
 		return
 	}
 
-	c.HTML(http.StatusOK, "job_type_edit.html", gin.H{
+	// Re-fill the rejected input of a failed update
+	var input dto.JobTypeUpdateDTO
+	if utils.OldInput(c, &input) {
+		job.Name, job.Status = input.Name, input.Status
+	}
+
+	utils.HTML(c, http.StatusOK, "job_type_edit.html", gin.H{
 		"title": "Edit Job Type",
 		"data":  job,
 	})
@@ -202,15 +196,16 @@ func AdminEditJobType(c *gin.Context) {
 func AdminUpdateJobType(c *gin.Context) {
 	id := c.Param("id")
 
-	var req dto.JobTypeStoreDTO
-	if err := c.ShouldBind(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req dto.JobTypeUpdateDTO
+	if valid, errs := utils.ValidateStruct(c, &req); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/job-type-edit/"+id)
 		return
 	}
 
 	var job models.JobType
 	if err := config.DB.NewSelect().Model(&job).Where("id = ?", id).Scan(c); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Job type not found"})
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Job type not found"))
 		return
 	}
 
@@ -221,9 +216,10 @@ func AdminUpdateJobType(c *gin.Context) {
 
 	_, err := config.DB.NewUpdate().Model(&job).Where("id = ?", id).Exec(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job type"})
+		utils.Fail(c, utils.Internal(err, "Failed to update job type"))
 		return
 	}
 
-	c.Redirect(http.StatusSeeOther, "/admin/job-type-list?success=Job+Type+updated+successfully!")
+	utils.FlashSuccess(c, "Job Type updated successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/job-type-list")
 }
@@ -1,19 +1,21 @@
 package controllers
 
 import (
-	"context"
+	"errors"
 	"gin-app/config"
 	"gin-app/internal/models"
+	"gin-app/internal/pkg/metrics"
 	"gin-app/internal/utils"
+	"log/slog"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
 func AdminLogin(c *gin.Context) {
-	c.HTML(http.StatusOK, "login.html", gin.H{
+	utils.HTML(c, http.StatusOK, "login.html", gin.H{
 		"title": "Login Page",
 	})
 }
@@ -24,115 +26,105 @@ func AdminLoginAction(c *gin.Context) {
 	remember := c.PostForm("remember") // "on" if checked
 
 	if email == "" || password == "" {
-		c.HTML(http.StatusOK, "login.html", gin.H{"error": "Email and password required"})
+		utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "Email and password required"})
 		return
 	}
 
+	// Brute-force protection (per IP and per account)
+	ctx := c.Request.Context()
+	if err := utils.CheckLoginAllowed(ctx, c.ClientIP(), email); err != nil {
+		var limitErr *utils.LoginLimitError
+		if errors.As(err, &limitErr) {
+			metrics.Login(utils.AdminScope.Name, metrics.LoginThrottled)
+			c.Header("Retry-After", strconv.Itoa(int(limitErr.RetryAfter.Seconds())+1))
+			utils.HTML(c, http.StatusTooManyRequests, "login.html", gin.H{"error": limitErr.Message(), "throttled": true})
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "Login limiter unavailable", "error", err)
+	}
+
 	var admin models.User
-	err := config.DB.NewSelect().Model(&admin).Where("email = ?", email).Scan(c.Request.Context())
+	err := config.DB.NewSelect().Model(&admin).Where("email = ?", email).Scan(ctx)
 	if err != nil {
-		c.HTML(http.StatusOK, "login.html", gin.H{"error": "Invalid email or password"})
+		loginFailed(c, email)
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)); err != nil {
-		c.HTML(http.StatusOK, "login.html", gin.H{"error": "Invalid email or password"})
+		loginFailed(c, email)
 		return
 	}
 
-	// Set TTL based on "remember me"
-	var accessTTL, refreshTTL time.Duration
-	if remember == "on" {
-		accessTTL, _ = time.ParseDuration("24h")  // 1 day access token
-		refreshTTL, _ = time.ParseDuration("30d") // 30 days refresh token
-	} else {
-		accessTTL, _ = time.ParseDuration("24h") // default 24h
-		refreshTTL, _ = time.ParseDuration("7d") // default 7 days
+	if !admin.IsActive() {
+		metrics.Login(utils.AdminScope.Name, metrics.LoginDenied)
+		utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "Your account has been disabled"})
+		return
 	}
 
-	// Generate tokens
-	accessToken, _ := utils.GenerateToken(admin.ID, config.AppConfig.App.JwtSecret, accessTTL)
-	refreshToken, _ := utils.GenerateToken(admin.ID, config.AppConfig.App.JwtSecret, refreshTTL)
+	// Only roles with panel access may log in here
+	allowed, err := models.UserHasPermission(c.Request.Context(), config.DB, &admin, models.PermissionAdminAccess)
+	if err != nil || !allowed {
+		metrics.Login(utils.AdminScope.Name, metrics.LoginDenied)
+		utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "You are not allowed to access the admin panel"})
+		return
+	}
 
-	// Store token as Redis key (simplified)
-	config.RedisClient.Set(config.Ctx, "admin_access:"+accessToken, admin.ID, accessTTL)
-	config.RedisClient.Set(config.Ctx, "admin_refresh:"+refreshToken, admin.ID, refreshTTL)
+	// Second factor first, tokens are only issued after the code is verified.
+	// The failure counters are reset only then, wrong codes count against the account.
+	if admin.TwoFactorEnabled() {
+		if err := utils.StartPendingLogin(c, admin.ID, remember == "on"); err != nil {
+			utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "Could not log you in, please try again"})
+			return
+		}
+		c.Redirect(http.StatusSeeOther, "/admin/login/2fa")
+		return
+	}
 
-	// Set cookies
-	c.SetCookie("admin_access", accessToken, int(accessTTL.Seconds()), "/", "", false, true)
-	c.SetCookie("admin_refresh", refreshToken, int(refreshTTL.Seconds()), "/", "", false, true)
+	// Set TTL based on "remember me"
+	refreshTTL := utils.RefreshTTL() // app.refresh_ttl
+	if remember == "on" {
+		refreshTTL = utils.RememberRefreshTTL() // app.remember_ttl
+	}
+
+	// Generate tokens, store them in Redis and set cookies
+	if err := utils.IssueAdminTokens(c, &admin, utils.AccessTTL(), refreshTTL); err != nil {
+		utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "Could not log you in, please try again"})
+		return
+	}
 
+	utils.ResetLoginFailures(ctx, email)
+	metrics.Login(utils.AdminScope.Name, metrics.LoginSuccess)
 	c.Redirect(http.StatusSeeOther, "/admin/dashboard")
 }
 
-func AdminForgetPassword(c *gin.Context) {
-	c.HTML(http.StatusOK, "forget-password.html", gin.H{
-		"title": "Forget Password",
-	})
+// loginFailed counts the failed attempt and re-renders the login form
+func loginFailed(c *gin.Context, email string) {
+	metrics.Login(utils.AdminScope.Name, metrics.LoginFailure)
+	if err := utils.RegisterLoginFailure(c.Request.Context(), c.ClientIP(), email); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to record login failure", "error", err)
+	}
+	utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "Invalid email or password"})
 }
 
 func AdminRefreshToken(c *gin.Context) {
 	refreshToken, err := c.Cookie("admin_refresh")
 	if err != nil || refreshToken == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing refresh token"})
+		utils.Fail(c, utils.Unauthorized("Missing refresh token"))
 		return
 	}
 
-	// Check Redis
-	val, err := config.RedisClient.Get(config.Ctx, "admin_refresh:"+refreshToken).Result()
-	if err != nil || val == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+	session, err := utils.RotateAdminRefreshToken(c, refreshToken)
+	if errors.Is(err, utils.ErrRefreshTokenReused) {
+		slog.WarnContext(c.Request.Context(), "Refresh token reuse detected, all sessions revoked", "user_id", session.UserID)
+		utils.ClearAdminCookies(c)
+		utils.Fail(c, utils.Unauthorized("Refresh token reuse detected, please log in again"))
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Token refreshed successfully"})
-}
-
-func AdminCreate(c *gin.Context) {
-	// আগে থেকে কোনো admin আছে কিনা চেক করুন
-	count, err := config.DB.NewSelect().
-		Model(&models.User{}).
-		Where("role = ?", "admin").
-		Count(context.Background())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "db error"})
-		return
-	}
-	if count > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Admin already exists"})
-		return
-	}
-
-	// ----- Static admin data -----
-	name := "Super Admin"
-	email := "admin@example.com"
-	password := "password"
-	role := "admin"
-
-	// Password hash
-	hashedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 12)
-
-	user := models.User{
-		Name:     name,
-		Email:    email,
-		Password: string(hashedPass),
-		Role:     role,
-	}
-
-	// Insert admin
-	_, err = config.DB.NewInsert().Model(&user).Exec(context.Background())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create admin"})
+		utils.ClearAdminCookies(c)
+		utils.Fail(c, utils.Unauthorized("Invalid or expired refresh token"))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Admin created successfully",
-		"admin": gin.H{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
-			"role":  user.Role,
-		},
-	})
+	c.JSON(http.StatusOK, gin.H{"message": "Token refreshed successfully"})
 }
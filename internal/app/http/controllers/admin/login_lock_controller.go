@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"gin-app/internal/utils"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Accounts locked by the login brute-force protection
+func AdminLoginLockList(c *gin.Context) {
+	locks, err := utils.ListLoginLocks(c.Request.Context())
+	if err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch locked accounts"))
+		return
+	}
+
+	sort.Slice(locks, func(i, j int) bool { return locks[i].Email < locks[j].Email })
+
+	utils.HTML(c, http.StatusOK, "login_lock_list.html", gin.H{
+		"title":    "Locked Accounts",
+		"PageName": "login_lock_list",
+		"data":     locks,
+	})
+}
+
+// Unlock account (AJAX)
+func AdminUnlockLogin(c *gin.Context) {
+	var input struct {
+		Email string `form:"email" json:"email" binding:"required"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		utils.Fail(c, utils.Validation("Invalid input", nil))
+		return
+	}
+
+	if err := utils.UnlockLogin(c.Request.Context(), input.Email); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to unlock account"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Account unlocked successfully"})
+}
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"gin-app/config"
+	"gin-app/internal/dto"
+	"gin-app/internal/models"
+	"gin-app/internal/pkg/mailer"
+	"gin-app/internal/utils"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Reset tokens live in Redis, like the admin_access: keys
+const (
+	passwordResetPrefix     = "admin_password_reset:"
+	passwordResetUserPrefix = "admin_password_reset_user:"
+	passwordResetTTL        = 30 * time.Minute
+)
+
+const (
+	forgetPasswordSentMsg = "If an account exists for that email, a password reset link has been sent."
+	invalidResetLinkMsg   = "This reset link is invalid or has expired. Please request a new one."
+)
+
+func AdminForgetPassword(c *gin.Context) {
+	data := gin.H{
+		"title": "Forget Password",
+	}
+	var input dto.ForgetPasswordDTO
+	if utils.OldInput(c, &input) {
+		data["data"] = input
+	}
+	utils.HTML(c, http.StatusOK, "forget-password.html", data)
+}
+
+// Send reset link
+func AdminForgetPasswordAction(c *gin.Context) {
+	var input dto.ForgetPasswordDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/forget-password")
+		return
+	}
+
+	// Always show the same message so emails can't be enumerated
+	user, err := models.GetUserByEmail(c.Request.Context(), config.DB, input.Email)
+	if err != nil {
+		utils.FlashSuccess(c, forgetPasswordSentMsg)
+		c.Redirect(http.StatusSeeOther, "/admin/forget-password")
+		return
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		utils.FlashFailure(c, err, "Could not create reset link, please try again")
+		c.Redirect(http.StatusSeeOther, "/admin/forget-password")
+		return
+	}
+
+	// Only the latest link stays valid
+	userKey := passwordResetUserPrefix + strconv.FormatInt(user.ID, 10)
+	if old, err := config.RedisClient.Get(c.Request.Context(), userKey).Result(); err == nil {
+		config.RedisClient.Del(c.Request.Context(), passwordResetPrefix+old)
+	}
+	config.RedisClient.Set(c.Request.Context(), passwordResetPrefix+token, user.ID, passwordResetTTL)
+	config.RedisClient.Set(c.Request.Context(), userKey, token, passwordResetTTL)
+
+	link := config.AppConfig.App.URL + "/admin/reset-password/" + token
+	err = mailer.Default().Send(c.Request.Context(), mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nUse the link below to reset your password. It expires in %d minutes and can only be used once.\n\n%s\n\nIf you did not request this, you can ignore this email.",
+			user.Name, int(passwordResetTTL.Minutes()), link,
+		),
+	})
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to send password reset mail", "error", err)
+	}
+
+	utils.FlashSuccess(c, forgetPasswordSentMsg)
+	c.Redirect(http.StatusSeeOther, "/admin/forget-password")
+}
+
+// Reset form
+func AdminResetPassword(c *gin.Context) {
+	token := c.Param("token")
+
+	if n, err := config.RedisClient.Exists(c.Request.Context(), passwordResetPrefix+token).Result(); err != nil || n == 0 {
+		utils.FlashError(c, invalidResetLinkMsg)
+		c.Redirect(http.StatusSeeOther, "/admin/forget-password")
+		return
+	}
+
+	utils.HTML(c, http.StatusOK, "reset-password.html", gin.H{
+		"title": "Reset Password",
+		"token": token,
+	})
+}
+
+// Update password
+func AdminResetPasswordAction(c *gin.Context) {
+	var input dto.ResetPasswordDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/reset-password/"+url.PathEscape(c.PostForm("token")))
+		return
+	}
+	// Checked before the token is used up, so the user can pick another password
+	if errs := utils.PasswordStrengthErrors(input.Password); errs != nil {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/reset-password/"+url.PathEscape(input.Token))
+		return
+	}
+
+	ctx := c.Request.Context()
+	tokenKey := passwordResetPrefix + input.Token
+	resetURL := "/admin/reset-password/" + url.PathEscape(input.Token)
+
+	// GETDEL makes the token single use; it is put back when the password
+	// can't be saved, so a server error doesn't burn the link
+	ttl, _ := config.RedisClient.PTTL(ctx, tokenKey).Result()
+	val, err := config.RedisClient.GetDel(ctx, tokenKey).Result()
+	if err != nil {
+		utils.FlashError(c, invalidResetLinkMsg)
+		c.Redirect(http.StatusSeeOther, "/admin/forget-password")
+		return
+	}
+	restoreToken := func() {
+		if ttl <= 0 {
+			return
+		}
+		if err := config.RedisClient.SetNX(ctx, tokenKey, val, ttl).Err(); err != nil {
+			slog.ErrorContext(ctx, "Failed to restore password reset token", "error", err)
+		}
+	}
+	userID, _ := strconv.ParseInt(val, 10, 64)
+
+	user, err := models.GetUserByID(ctx, config.DB, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		utils.FlashError(c, invalidResetLinkMsg)
+		c.Redirect(http.StatusSeeOther, "/admin/forget-password")
+		return
+	}
+	if err != nil {
+		restoreToken()
+		utils.FlashFailure(c, err, "Failed to update password, please try again")
+		c.Redirect(http.StatusSeeOther, resetURL)
+		return
+	}
+
+	hashed, err := models.HashPassword(input.Password)
+	if err != nil {
+		restoreToken()
+		utils.FlashFailure(c, err, "Failed to update password, please try again")
+		c.Redirect(http.StatusSeeOther, resetURL)
+		return
+	}
+
+	user.Password = hashed
+	user.BeforeUpdate()
+	if _, err := config.DB.NewUpdate().Model(user).Column("password", "updated_at").WherePK().Exec(ctx); err != nil {
+		restoreToken()
+		utils.FlashFailure(c, err, "Failed to update password, please try again")
+		c.Redirect(http.StatusSeeOther, resetURL)
+		return
+	}
+	config.RedisClient.Del(ctx, passwordResetUserPrefix+val)
+
+	// Sign out every device that used the old password
+	if err := utils.RevokeUserTokens(c.Request.Context(), user.ID); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to revoke sessions", "user_id", user.ID, "error", err)
+	}
+
+	utils.FlashSuccess(c, "Your password has been reset. Please log in with your new password.")
+	c.Redirect(http.StatusSeeOther, "/admin/login")
+}
@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"context"
+	"gin-app/config"
+	"gin-app/internal/dto"
+	"gin-app/internal/models"
+	"gin-app/internal/utils"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
+)
+
+// permissionGroup groups permissions by the prefix of their slug ("category.delete" → "Category")
+type permissionGroup struct {
+	Name        string
+	Permissions []models.Permission
+}
+
+type roleRow struct {
+	models.Role
+	PermissionCount int
+	UserCount       int
+}
+
+func AdminRoleList(c *gin.Context) {
+	var roles []models.Role
+	if err := config.DB.NewSelect().Model(&roles).Order("id ASC").Scan(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch roles"))
+		return
+	}
+
+	rows := make([]roleRow, 0, len(roles))
+	for _, role := range roles {
+		permCount, _ := config.DB.NewSelect().Model((*models.RolePermission)(nil)).Where("role_id = ?", role.ID).Count(c)
+		userCount, _ := config.DB.NewSelect().Model((*models.User)(nil)).Where("role = ?", role.Slug).Count(c)
+		rows = append(rows, roleRow{Role: role, PermissionCount: permCount, UserCount: userCount})
+	}
+
+	utils.HTML(c, http.StatusOK, "role_list.html", gin.H{
+		"title":    "Role List",
+		"PageName": "role_list",
+		"data":     rows,
+	})
+}
+
+// Role create page
+func AdminRoleCreate(c *gin.Context) {
+	groups, err := loadPermissionGroups(c)
+	if err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch permissions"))
+		return
+	}
+
+	selected := map[int64]bool{}
+	data := gin.H{
+		"title":    "Create Role",
+		"groups":   groups,
+		"selected": selected,
+	}
+	var input dto.RoleStoreDTO
+	if utils.OldInput(c, &input) {
+		data["data"] = input // re-fill after a failed submit
+		for _, id := range input.PermissionIDs {
+			selected[id] = true
+		}
+	}
+	utils.HTML(c, http.StatusOK, "role_create.html", data)
+}
+
+// Role store
+func AdminRoleStore(c *gin.Context) {
+	var input dto.RoleStoreDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/role-create")
+		return
+	}
+
+	slug := utils.MakeSlug(input.Name)
+	if slug == "" {
+		slug = "not-available"
+	}
+
+	exists, _ := config.DB.NewSelect().Model((*models.Role)(nil)).Where("slug = ?", slug).Exists(c)
+	if exists {
+		utils.FlashInput(c, map[string]string{"Name": "A role with this name already exists"})
+		c.Redirect(http.StatusSeeOther, "/admin/role-create")
+		return
+	}
+
+	role := models.Role{
+		Name:        input.Name,
+		Slug:        slug,
+		Description: input.Description,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	err := config.DB.RunInTx(c, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(&role).Exec(ctx); err != nil {
+			return err
+		}
+		return models.SyncRolePermissions(ctx, tx, role.ID, input.PermissionIDs)
+	})
+	if err != nil {
+		utils.FlashFailure(c, err, "Failed to create role")
+		utils.FlashInput(c, nil)
+		c.Redirect(http.StatusSeeOther, "/admin/role-create")
+		return
+	}
+
+	utils.FlashSuccess(c, "Role created successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/role-list")
+}
+
+// Role edit page
+func AdminEditRole(c *gin.Context) {
+	var role models.Role
+	if err := config.DB.NewSelect().Model(&role).Where("id = ?", c.Param("id")).Scan(c); err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Role not found"))
+		return
+	}
+
+	ids, err := models.GetRolePermissionIDs(c, config.DB, role.ID)
+	if err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch permissions"))
+		return
+	}
+
+	// Re-fill the rejected input of a failed update
+	var input dto.RoleUpdateDTO
+	if utils.OldInput(c, &input) {
+		role.Name, role.Description, ids = input.Name, input.Description, input.PermissionIDs
+	}
+
+	renderRoleForm(c, http.StatusOK, "role_edit.html", gin.H{
+		"title": "Edit Role",
+		"data":  role,
+	}, ids)
+}
+
+// Role update
+func AdminUpdateRole(c *gin.Context) {
+	var role models.Role
+	if err := config.DB.NewSelect().Model(&role).Where("id = ?", c.Param("id")).Scan(c); err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Role not found"))
+		return
+	}
+
+	editURL := "/admin/role-edit/" + c.Param("id")
+
+	var input dto.RoleUpdateDTO
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, editURL)
+		return
+	}
+
+	// Slug stays the same, users.role points at it
+	role.Name = input.Name
+	role.Description = input.Description
+	role.UpdatedAt = time.Now()
+
+	err := config.DB.RunInTx(c, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().Model(&role).Column("name", "description", "updated_at").WherePK().Exec(ctx); err != nil {
+			return err
+		}
+		return models.SyncRolePermissions(ctx, tx, role.ID, input.PermissionIDs)
+	})
+	if err != nil {
+		utils.FlashFailure(c, err, "Failed to update role")
+		utils.FlashInput(c, nil)
+		c.Redirect(http.StatusSeeOther, editURL)
+		return
+	}
+
+	utils.FlashSuccess(c, "Role updated successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/role-list")
+}
+
+// Role delete
+func AdminDeleteRole(c *gin.Context) {
+	var role models.Role
+	if err := config.DB.NewSelect().Model(&role).Where("id = ?", c.Param("id")).Scan(c); err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Role not found"))
+		return
+	}
+
+	if role.IsSuperAdmin() || role.Slug == models.RoleCustomer {
+		utils.Fail(c, utils.Validation("Built-in roles cannot be deleted", nil))
+		return
+	}
+
+	inUse, err := config.DB.NewSelect().Model((*models.User)(nil)).Where("role = ?", role.Slug).Exists(c)
+	if err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to check the users of the role"))
+		return
+	}
+	if inUse {
+		utils.Fail(c, utils.Conflict("Role is assigned to users, reassign them first"))
+		return
+	}
+
+	if _, err := config.DB.NewDelete().Model(&role).WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to delete role"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted successfully"})
+}
+
+// Users with their roles
+func AdminUserRoleList(c *gin.Context) {
+	search := c.Query("search")
+	role := c.Query("role")
+
+	// Cursor pagination
+	lastID, limit := utils.GetCursorPagination(c)
+
+	query := config.DB.NewSelect().Model((*models.User)(nil))
+	if search != "" {
+		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if lastID > 0 {
+		query = query.Where("id > ?", lastID)
+	}
+
+	var users []models.User
+	if err := query.Order("id ASC").Limit(limit).Scan(c, &users); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch users"))
+		return
+	}
+
+	var nextCursor int64
+	if len(users) > 0 {
+		nextCursor = users[len(users)-1].ID
+	}
+
+	totalCount, err := config.DB.NewSelect().Model((*models.User)(nil)).Count(c)
+	if err != nil {
+		totalCount = 0
+	}
+
+	utils.HTML(c, http.StatusOK, "user_role_list.html", gin.H{
+		"title":      "Assign Roles",
+		"PageName":   "user_role_list",
+		"data":       users,
+		"roles":      loadRoles(c),
+		"nextCursor": nextCursor,
+		"limit":      limit,
+		"total":      totalCount,
+		"filters": gin.H{
+			"search": search,
+			"role":   role,
+		},
+	})
+}
+
+// Assign role (AJAX)
+func AdminAssignUserRole(c *gin.Context) {
+	var input dto.UserRoleDTO
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.Fail(c, utils.Validation(errs["Role"], errs))
+		return
+	}
+
+	var user models.User
+	if err := config.DB.NewSelect().Model(&user).Where("id = ?", c.Param("id")).Scan(c); err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "User not found"))
+		return
+	}
+
+	if !roleExists(c, input.Role) {
+		utils.Fail(c, utils.Validation("Unknown role", map[string]string{"Role": "Unknown role"}))
+		return
+	}
+	if !canManageUser(c, &user) {
+		utils.Fail(c, utils.Forbidden(superAdminOnlyMsg))
+		return
+	}
+	if msg := roleChangeError(c, user.Role, input.Role); msg != "" {
+		utils.Fail(c, utils.Forbidden(msg))
+		return
+	}
+
+	// Don't let an admin lock themselves out
+	if admin := utils.CurrentAdmin(c); admin != nil && admin.ID == user.ID && input.Role != user.Role {
+		utils.Fail(c, utils.Validation("You cannot change your own role", nil))
+		return
+	}
+
+	user.Role = input.Role
+	user.BeforeUpdate()
+	if _, err := config.DB.NewUpdate().Model(&user).Column("role", "updated_at").WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to assign role"))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role assigned successfully", "role": user.Role})
+}
+
+// renderRoleForm renders the create/edit page with grouped permission checkboxes
+func renderRoleForm(c *gin.Context, status int, name string, data gin.H, selectedIDs []int64) {
+	groups, err := loadPermissionGroups(c)
+	if err != nil {
+		_ = c.Error(err)
+		data["error"] = "Failed to fetch permissions"
+	}
+
+	selected := make(map[int64]bool, len(selectedIDs))
+	for _, id := range selectedIDs {
+		selected[id] = true
+	}
+
+	data["groups"] = groups
+	data["selected"] = selected
+	utils.HTML(c, status, name, data)
+}
+
+func loadPermissionGroups(c *gin.Context) ([]permissionGroup, error) {
+	var permissions []models.Permission
+	if err := config.DB.NewSelect().Model(&permissions).Order("slug ASC").Scan(c); err != nil {
+		return nil, err
+	}
+
+	var groups []permissionGroup
+	index := map[string]int{}
+	for _, p := range permissions {
+		prefix, _, _ := strings.Cut(p.Slug, ".")
+		i, ok := index[prefix]
+		if !ok {
+			i = len(groups)
+			index[prefix] = i
+			groups = append(groups, permissionGroup{Name: utils.ToTitleCase(strings.ReplaceAll(prefix, "_", " "))})
+		}
+		groups[i].Permissions = append(groups[i].Permissions, p)
+	}
+	return groups, nil
+}
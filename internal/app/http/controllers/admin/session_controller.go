@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"gin-app/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Active sessions (devices) of the logged in admin
+func AdminSessionList(c *gin.Context) {
+	admin := utils.CurrentAdmin(c)
+	ctx := c.Request.Context()
+	current := c.GetString(utils.AdminSessionContextKey)
+
+	var sessions []utils.Session
+	for _, scope := range utils.TokenScopes {
+		list, err := utils.ListSessions(ctx, scope, admin.ID)
+		if err != nil {
+			utils.Fail(c, utils.Internal(err, "Failed to fetch sessions"))
+			return
+		}
+		for i := range list {
+			list[i].Current = scope.Name == utils.AdminScope.Name && list[i].ID == current
+		}
+		sessions = append(sessions, list...)
+	}
+
+	utils.HTML(c, http.StatusOK, "session_list.html", gin.H{
+		"title":    "My Sessions",
+		"PageName": "session_list",
+		"data":     sessions,
+	})
+}
+
+// Revoke one session (AJAX)
+func AdminRevokeSession(c *gin.Context) {
+	admin := utils.CurrentAdmin(c)
+
+	scope, ok := utils.ScopeByName(c.Param("scope"))
+	if !ok {
+		utils.Fail(c, utils.NotFound("Session not found"))
+		return
+	}
+
+	sessionID := c.Param("id")
+	err := utils.RevokeSession(c.Request.Context(), scope, admin.ID, sessionID)
+	if errors.Is(err, utils.ErrSessionNotFound) {
+		utils.Fail(c, utils.NotFound("Session not found"))
+		return
+	}
+	if err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to revoke session"))
+		return
+	}
+
+	// Ending the session of this browser logs it out
+	if scope.Name == utils.AdminScope.Name && sessionID == c.GetString(utils.AdminSessionContextKey) {
+		utils.ClearAdminCookies(c)
+		c.JSON(http.StatusOK, gin.H{"message": "You have been logged out", "redirect": "/admin/login"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+}
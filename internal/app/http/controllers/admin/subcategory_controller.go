@@ -12,7 +12,6 @@ import (
 )
 
 func AdminSubCategoryList(c *gin.Context) {
-	successMsg := c.Query("success")
 	// Filters
 	search := c.Query("search")
 	categoryID := c.Query("category_id")
@@ -45,11 +44,7 @@ func AdminSubCategoryList(c *gin.Context) {
 	// Fetch
 	var data []models.Subcategory
 	if err := query.Scan(c, &data); err != nil {
-		c.HTML(http.StatusInternalServerError, "subcategory_list.html", gin.H{
-			"title": "Subcategory List",
-			"error": "Failed to fetch subcategories: " + err.Error(),
-			"data":  []models.Subcategory{},
-		})
+		utils.Fail(c, utils.Internal(err, "Failed to fetch subcategories"))
 		return
 	}
 
@@ -75,14 +70,11 @@ func AdminSubCategoryList(c *gin.Context) {
 		Scan(c.Request.Context())
 
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "subcategory_list.html", gin.H{
-			"title": "Subcategory List",
-			"error": "Failed to fetch categories: " + err.Error(),
-		})
+		utils.Fail(c, utils.Internal(err, "Failed to fetch categories"))
 		return
 	}
 	// Render
-	c.HTML(http.StatusOK, "subcategory_list.html", gin.H{
+	utils.HTML(c, http.StatusOK, "subcategory_list.html", gin.H{
 		"title":      "Subcategory List",
 		"PageName":   "subcategory_list",
 		"data":       data,
@@ -95,7 +87,6 @@ func AdminSubCategoryList(c *gin.Context) {
 			"category_id": categoryID,
 			"created_at":  createdAt,
 		},
-		"success": successMsg, // pass to template
 	})
 }
 
@@ -109,19 +100,18 @@ func AdminSubCategoryCreate(c *gin.Context) {
 		Scan(c.Request.Context())
 
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "subcategory_create.html", gin.H{
-			"title": "Subcategory List",
-			"error": "Failed to fetch categories: " + err.Error(),
-		})
+		utils.Fail(c, utils.Internal(err, "Failed to fetch categories"))
 		return
 	}
-	// ✅ success query param ধরুন
-	successMsg := c.Query("success")
-	c.HTML(http.StatusOK, "subcategory_create.html", gin.H{
+	data := gin.H{
 		"title":      "Subcategory Create",
 		"categories": categories,
-		"success":    successMsg,
-	})
+	}
+	var input dto.SubcategoryStoreDTO
+	if utils.OldInput(c, &input) {
+		data["data"] = input // re-fill after a failed submit
+	}
+	utils.HTML(c, http.StatusOK, "subcategory_create.html", data)
 }
 
 // sub category store
@@ -130,27 +120,8 @@ func AdminSubCategoryStore(c *gin.Context) {
 
 	//  Bind + Validate
 	if valid, errs := utils.ValidateStruct(c, &input); !valid {
-		// categories আনতে হবে, কারণ error হলে আবার form show করতে হবে
-		var categories []models.Category
-		err := config.DB.NewSelect().
-			Model(&categories).
-			Where("status = ?", 1).
-			Scan(c.Request.Context())
-
-		if err != nil {
-			c.HTML(http.StatusInternalServerError, "subcategory_create.html", gin.H{
-				"title": "Create Subcategory",
-				"error": "Failed to fetch categories: " + err.Error(),
-			})
-			return
-		}
-
-		c.HTML(http.StatusBadRequest, "subcategory_create.html", gin.H{
-			"title":      "Create Subcategory",
-			"errors":     errs,
-			"data":       input,
-			"categories": categories,
-		})
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/subcategory-create")
 		return
 	}
 
@@ -172,16 +143,15 @@ func AdminSubCategoryStore(c *gin.Context) {
 
 	// Database insert
 	if _, err := config.DB.NewInsert().Model(&subcategory).Exec(c); err != nil {
-		c.HTML(http.StatusInternalServerError, "subcategory_create.html", gin.H{
-			"title":  "Create Subcategory",
-			"errors": map[string]string{"DB": "Failed to create subcategory: " + err.Error()},
-			"data":   input,
-		})
+		utils.FlashFailure(c, err, "Failed to create subcategory")
+		utils.FlashInput(c, nil)
+		c.Redirect(http.StatusSeeOther, "/admin/subcategory-create")
 		return
 	}
 
 	// ✅ Redirect করে আবার Create form call করুন
-	c.Redirect(http.StatusSeeOther, "/admin/subcategory-create?success=Subcategory+created+successfully!")
+	utils.FlashSuccess(c, "Subcategory created successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/subcategory-create")
 }
 
 // edit
@@ -190,9 +160,7 @@ func AdminEditSubCategory(c *gin.Context) {
 	// Fetch subcategory by ID
 	var subcategory models.Subcategory
 	if err := config.DB.NewSelect().Model(&subcategory).Where("id = ?", id).Scan(c); err != nil {
-		c.HTML(http.StatusNotFound, "404.html", gin.H{
-			"title": "Subcategory Not Found",
-		})
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Subcategory not found"))
 		return
 	}
 
@@ -205,14 +173,17 @@ func AdminEditSubCategory(c *gin.Context) {
 		Scan(c.Request.Context())
 
 	if err != nil {
-		c.HTML(http.StatusInternalServerError, "subcategory_create.html", gin.H{
-			"title": "Subcategory List",
-			"error": "Failed to fetch categories: " + err.Error(),
-		})
+		utils.Fail(c, utils.Internal(err, "Failed to fetch categories"))
 		return
 	}
 
-	c.HTML(http.StatusOK, "subcategory_edit.html", gin.H{
+	// Re-fill the rejected input of a failed update
+	var input dto.SubcategoryUpdateDTO
+	if utils.OldInput(c, &input) {
+		subcategory.Name, subcategory.CategoryID, subcategory.Status = input.Name, input.CategoryID, input.Status
+	}
+
+	utils.HTML(c, http.StatusOK, "subcategory_edit.html", gin.H{
 		"title":      "Edit Subcategory",
 		"PageName":   "subcategory_edit",
 		"data":       subcategory,
@@ -223,39 +194,19 @@ func AdminEditSubCategory(c *gin.Context) {
 // update subcategory
 func AdminUpdateSubCategory(c *gin.Context) {
 	var input dto.SubcategoryUpdateDTO
+	editURL := "/admin/subcategory-edit/" + c.Param("id")
 
 	//  Bind + Validate
 	if valid, errs := utils.ValidateStruct(c, &input); !valid {
-		// categories আনতে হবে, কারণ error হলে আবার form show করতে হবে
-		var categories []models.Category
-		err := config.DB.NewSelect().
-			Model(&categories).
-			Where("status = ?", 1).
-			Scan(c.Request.Context())
-
-		if err != nil {
-			c.HTML(http.StatusInternalServerError, "subcategory_edit.html", gin.H{
-				"title": "Edit Subcategory",
-				"error": "Failed to fetch categories: " + err.Error(),
-			})
-			return
-		}
-
-		c.HTML(http.StatusBadRequest, "subcategory_edit.html", gin.H{
-			"title":      "Edit Subcategory",
-			"errors":     errs,
-			"data":       input,
-			"categories": categories,
-		})
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, editURL)
 		return
 	}
 
 	// Fetch existing subcategory
 	var subcategory models.Subcategory
 	if err := config.DB.NewSelect().Model(&subcategory).Where("id = ?", c.Param("id")).Scan(c.Request.Context()); err != nil {
-		c.HTML(http.StatusNotFound, "404.html", gin.H{
-			"title": "Subcategory Not Found",
-		})
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Subcategory not found"))
 		return
 	}
 
@@ -267,16 +218,15 @@ func AdminUpdateSubCategory(c *gin.Context) {
 
 	// Database update
 	if _, err := config.DB.NewUpdate().Model(&subcategory).Where("id = ?", subcategory.ID).Exec(c); err != nil {
-		c.HTML(http.StatusInternalServerError, "subcategory_edit.html", gin.H{
-			"title":  "Edit Subcategory",
-			"errors": map[string]string{"DB": "Failed to update subcategory: " + err.Error()},
-			"data":   input,
-		})
+		utils.FlashFailure(c, err, "Failed to update subcategory")
+		utils.FlashInput(c, nil)
+		c.Redirect(http.StatusSeeOther, editURL)
 		return
 	}
 
 	// ✅ Redirect to list
-	c.Redirect(http.StatusSeeOther, "/admin/subcategory-list?success=Subcategory+updated+successfully!")
+	utils.FlashSuccess(c, "Subcategory updated successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/subcategory-list")
 }
 
 // delete
@@ -284,11 +234,13 @@ func AdminDeleteSubCategory(c *gin.Context) {
 	id := c.Param("id")
 
 	// Database delete
-	if _, err := config.DB.NewDelete().Model(&models.Subcategory{}).Where("id = ?", id).Exec(c); err != nil {
-		c.HTML(http.StatusInternalServerError, "subcategory_list.html", gin.H{
-			"title":  "Subcategory List",
-			"errors": map[string]string{"DB": "Failed to delete subcategory: " + err.Error()},
-		})
+	res, err := config.DB.NewDelete().Model(&models.Subcategory{}).Where("id = ?", id).Exec(c)
+	if err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to delete subcategory"))
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		utils.Fail(c, utils.NotFound("Subcategory not found"))
 		return
 	}
 
@@ -302,14 +254,14 @@ func AdminToggleSubCategoryStatus(c *gin.Context) {
 
 	var subcategory models.Subcategory
 	if err := config.DB.NewSelect().Model(&subcategory).Where("id = ?", id).Scan(c.Request.Context()); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Subcategory not found"})
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Subcategory not found"))
 		return
 	}
 
 	subcategory.Status = 1 - subcategory.Status
 	_, err := config.DB.NewUpdate().Model(&subcategory).Where("id = ?", id).Exec(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update subcategory status"})
+		utils.Fail(c, utils.Internal(err, "Failed to update subcategory status"))
 		return
 	}
 
@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"gin-app/config"
+	"gin-app/internal/dto"
+	"gin-app/internal/models"
+	"gin-app/internal/pkg/metrics"
+	"gin-app/internal/pkg/totp"
+	"gin-app/internal/utils"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
+)
+
+// Second login step (after the password)
+func AdminTwoFactorChallenge(c *gin.Context) {
+	if _, err := utils.GetPendingLogin(c); err != nil {
+		c.Redirect(http.StatusSeeOther, "/admin/login")
+		return
+	}
+
+	utils.HTML(c, http.StatusOK, "login_2fa.html", gin.H{
+		"title": "Two-Factor Authentication",
+	})
+}
+
+func AdminTwoFactorChallengeAction(c *gin.Context) {
+	pending, err := utils.GetPendingLogin(c)
+	if err != nil {
+		utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "Your login session expired, please log in again"})
+		return
+	}
+
+	var input dto.TwoFactorCodeDTO
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.HTML(c, http.StatusBadRequest, "login_2fa.html", gin.H{
+			"title":  "Two-Factor Authentication",
+			"errors": errs,
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	admin, err := models.GetUserByID(ctx, config.DB, pending.UserID)
+	if err != nil || !admin.IsActive() || !admin.TwoFactorEnabled() {
+		utils.ClearPendingLogin(c, pending)
+		utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "Your login session expired, please log in again"})
+		return
+	}
+
+	// Wrong codes count against the account like wrong passwords, so logging in
+	// again with the password doesn't give a fresh set of guesses
+	if err := utils.CheckLoginAllowed(ctx, c.ClientIP(), admin.Email); err != nil {
+		var limitErr *utils.LoginLimitError
+		if errors.As(err, &limitErr) {
+			metrics.Login(utils.AdminScope.Name, metrics.LoginThrottled)
+			utils.ClearPendingLogin(c, pending)
+			c.Header("Retry-After", strconv.Itoa(int(limitErr.RetryAfter.Seconds())+1))
+			utils.HTML(c, http.StatusTooManyRequests, "login.html", gin.H{"error": limitErr.Message(), "throttled": true})
+			return
+		}
+		slog.ErrorContext(ctx, "Login limiter unavailable", "error", err)
+	}
+
+	if !utils.VerifySecondFactor(ctx, admin, input.Code) {
+		metrics.Login(utils.AdminScope.Name, metrics.LoginFailure)
+		if err := utils.RegisterLoginFailure(ctx, c.ClientIP(), admin.Email); err != nil {
+			slog.ErrorContext(ctx, "Failed to record login failure", "error", err)
+		}
+		remaining := utils.FailPendingLogin(c, pending)
+		if remaining == 0 {
+			utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "Too many invalid codes, please log in again"})
+			return
+		}
+		utils.HTML(c, http.StatusOK, "login_2fa.html", gin.H{
+			"title": "Two-Factor Authentication",
+			"error": "Invalid authentication code",
+		})
+		return
+	}
+
+	utils.ClearPendingLogin(c, pending)
+
+	refreshTTL := utils.RefreshTTL()
+	if pending.Remember {
+		refreshTTL = utils.RememberRefreshTTL()
+	}
+	if err := utils.IssueAdminTokens(c, admin, utils.AccessTTL(), refreshTTL); err != nil {
+		utils.HTML(c, http.StatusOK, "login.html", gin.H{"error": "Could not log you in, please try again"})
+		return
+	}
+
+	utils.ResetLoginFailures(ctx, admin.Email)
+	metrics.Login(utils.AdminScope.Name, metrics.LoginSuccess)
+	c.Redirect(http.StatusSeeOther, "/admin/dashboard")
+}
+
+// 2FA settings of the logged in admin
+func AdminTwoFactor(c *gin.Context) {
+	renderTwoFactorPage(c, http.StatusOK, gin.H{})
+}
+
+// Confirm the enrollment with a first code
+func AdminTwoFactorEnable(c *gin.Context) {
+	admin := utils.CurrentAdmin(c)
+	if admin.TwoFactorEnabled() {
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	var input dto.TwoFactorCodeDTO
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	ctx := c.Request.Context()
+	secret, err := utils.TwoFactorSetupSecret(ctx, admin.ID)
+	if err != nil || !utils.VerifyTOTP(ctx, admin.ID, secret, input.Code) {
+		utils.FlashInput(c, map[string]string{"Code": "Invalid authentication code, check your device time and try again"})
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	admin.TotpSecret = secret
+	admin.TotpEnabledAt = time.Now()
+	admin.BeforeUpdate()
+
+	var codes []string
+	err = config.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().Model(admin).Column("totp_secret", "totp_enabled_at", "updated_at").WherePK().Exec(ctx); err != nil {
+			return err
+		}
+		var genErr error
+		codes, genErr = models.GenerateRecoveryCodes(ctx, tx, admin.ID)
+		return genErr
+	})
+	if err != nil {
+		admin.TotpSecret, admin.TotpEnabledAt = "", time.Time{}
+		utils.FlashFailure(c, err, "Failed to enable two-factor authentication")
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	utils.ClearTwoFactorSetup(ctx, admin.ID)
+
+	renderTwoFactorPage(c, http.StatusOK, gin.H{
+		"success":        "Two-factor authentication enabled. Store these recovery codes somewhere safe, they are shown only once.",
+		"recovery_codes": codes,
+	})
+}
+
+// Turn 2FA off (needs the password)
+func AdminTwoFactorDisable(c *gin.Context) {
+	admin := utils.CurrentAdmin(c)
+
+	var input dto.TwoFactorDisableDTO
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+	if !models.CheckPassword(input.Password, admin.Password) {
+		utils.FlashInput(c, map[string]string{"Password": "Password is incorrect"})
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	admin.TotpSecret = ""
+	admin.TotpEnabledAt = time.Time{}
+	admin.BeforeUpdate()
+
+	err := config.DB.RunInTx(c, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().Model(admin).Column("totp_secret", "totp_enabled_at", "updated_at").WherePK().Exec(ctx); err != nil {
+			return err
+		}
+		return models.DeleteRecoveryCodes(ctx, tx, admin.ID)
+	})
+	if err != nil {
+		utils.FlashFailure(c, err, "Failed to disable two-factor authentication")
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	utils.FlashSuccess(c, "Two-factor authentication disabled")
+	c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+}
+
+// New set of recovery codes (needs a current code)
+func AdminTwoFactorRecoveryCodes(c *gin.Context) {
+	admin := utils.CurrentAdmin(c)
+	if !admin.TwoFactorEnabled() {
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	var input dto.TwoFactorCodeDTO
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	ctx := c.Request.Context()
+	if !utils.VerifyTOTP(ctx, admin.ID, admin.TotpSecret, input.Code) {
+		utils.FlashInput(c, map[string]string{"Code": "Invalid authentication code"})
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	// The old codes are only deleted together with the insert of the new ones
+	var codes []string
+	err := config.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var genErr error
+		codes, genErr = models.GenerateRecoveryCodes(ctx, tx, admin.ID)
+		return genErr
+	})
+	if err != nil {
+		utils.FlashFailure(c, err, "Failed to create recovery codes")
+		c.Redirect(http.StatusSeeOther, "/admin/two-factor")
+		return
+	}
+
+	renderTwoFactorPage(c, http.StatusOK, gin.H{
+		"success":        "New recovery codes created, the old ones no longer work.",
+		"recovery_codes": codes,
+	})
+}
+
+func renderTwoFactorPage(c *gin.Context, status int, data gin.H) {
+	admin := utils.CurrentAdmin(c)
+	ctx := c.Request.Context()
+
+	data["title"] = "Two-Factor Authentication"
+	data["PageName"] = "two_factor"
+	data["enabled"] = admin.TwoFactorEnabled()
+
+	if admin.TwoFactorEnabled() {
+		remaining, _ := models.CountUnusedRecoveryCodes(ctx, config.DB, admin.ID)
+		data["enabled_at"] = admin.TotpEnabledAt
+		data["remaining_codes"] = remaining
+	} else {
+		secret, err := utils.TwoFactorSetupSecret(ctx, admin.ID)
+		if err != nil {
+			_ = c.Error(err)
+			data["error"] = "Could not create a two-factor secret"
+		} else {
+			data["secret"] = secret
+			// otpauth:// is not a safe URL scheme for html/template, the value is built by us
+			data["otpauth_uri"] = template.URL(totp.URI(config.AppConfig.App.Name, admin.Email, secret))
+		}
+	}
+
+	utils.HTML(c, status, "two_factor.html", data)
+}
@@ -0,0 +1,423 @@
+package controllers
+
+import (
+	"gin-app/config"
+	"gin-app/internal/dto"
+	"gin-app/internal/models"
+	"gin-app/internal/utils"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func AdminUserList(c *gin.Context) {
+	// Filters
+	search := c.Query("search")
+	role := c.Query("role")
+	status := c.Query("status")
+
+	// Cursor pagination
+	lastID, limit := utils.GetCursorPagination(c)
+
+	// Base query
+	query := config.DB.NewSelect().Model((*models.User)(nil))
+
+	if search != "" {
+		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	// Cursor condition
+	if lastID > 0 {
+		query = query.Where("id > ?", lastID)
+	}
+
+	var users []models.User
+	if err := query.Order("id ASC").Limit(limit).Scan(c, &users); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch users"))
+		return
+	}
+
+	// Next cursor
+	var nextCursor int64
+	if len(users) > 0 {
+		nextCursor = users[len(users)-1].ID
+	}
+
+	totalCount, err := config.DB.NewSelect().Model((*models.User)(nil)).Count(c)
+	if err != nil {
+		totalCount = 0
+	}
+
+	utils.HTML(c, http.StatusOK, "user_list.html", gin.H{
+		"title":      "User List",
+		"PageName":   "user_list",
+		"data":       users,
+		"roles":      loadRoles(c),
+		"me":         utils.CurrentAdmin(c).ID,
+		"nextCursor": nextCursor,
+		"limit":      limit,
+		"total":      totalCount,
+		"filters": gin.H{
+			"search": search,
+			"role":   role,
+			"status": status,
+		},
+	})
+}
+
+// Create page
+func AdminUserCreate(c *gin.Context) {
+	input := dto.UserStoreDTO{Role: models.RoleCustomer, Status: models.UserStatusActive}
+	utils.OldInput(c, &input) // re-fill after a failed submit, passwords are never kept
+
+	utils.HTML(c, http.StatusOK, "user_create.html", gin.H{
+		"title":    "Create User",
+		"PageName": "user_create",
+		"roles":    loadRoles(c),
+		"data":     input,
+	})
+}
+
+// User store
+func AdminUserStore(c *gin.Context) {
+	var input dto.UserStoreDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/user-create")
+		return
+	}
+
+	if errs := utils.PasswordStrengthErrors(input.Password); errs != nil {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/user-create")
+		return
+	}
+
+	input.Email = strings.ToLower(strings.TrimSpace(input.Email))
+	if errs := validateUserFields(c, 0, input.Email, models.RoleCustomer, input.Role); errs != nil {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, "/admin/user-create")
+		return
+	}
+
+	hashed, err := models.HashPassword(input.Password)
+	if err != nil {
+		utils.FlashFailure(c, err, "Failed to hash password")
+		utils.FlashInput(c, nil)
+		c.Redirect(http.StatusSeeOther, "/admin/user-create")
+		return
+	}
+
+	user := models.User{
+		Name:      input.Name,
+		Email:     input.Email,
+		Password:  hashed,
+		Role:      input.Role,
+		Status:    input.Status,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if _, err := config.DB.NewInsert().Model(&user).Exec(c); err != nil {
+		utils.FlashFailure(c, err, "Failed to create user")
+		utils.FlashInput(c, nil)
+		c.Redirect(http.StatusSeeOther, "/admin/user-create")
+		return
+	}
+
+	utils.FlashSuccess(c, "User created successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/user-list")
+}
+
+// User edit page
+func AdminEditUser(c *gin.Context) {
+	var user models.User
+	if err := config.DB.NewSelect().Model(&user).Where("id = ?", c.Param("id")).Scan(c); err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "User Not Found"))
+		return
+	}
+
+	if !canManageUser(c, &user) {
+		utils.Fail(c, utils.Forbidden(superAdminOnlyMsg))
+		return
+	}
+
+	// Re-fill the rejected input of a failed profile update
+	var input dto.UserUpdateDTO
+	if utils.OldInput(c, &input) {
+		user.Name, user.Email, user.Role, user.Status = input.Name, input.Email, input.Role, input.Status
+	}
+
+	utils.HTML(c, http.StatusOK, "user_edit.html", gin.H{
+		"title":    "Edit User",
+		"PageName": "user_edit",
+		"roles":    loadRoles(c),
+		"data":     user,
+	})
+}
+
+// User update
+func AdminUpdateUser(c *gin.Context) {
+	var user models.User
+	if err := config.DB.NewSelect().Model(&user).Where("id = ?", c.Param("id")).Scan(c); err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "User Not Found"))
+		return
+	}
+
+	if !canManageUser(c, &user) {
+		utils.Fail(c, utils.Forbidden(superAdminOnlyMsg))
+		return
+	}
+
+	editURL := "/admin/user-edit/" + c.Param("id")
+
+	var input dto.UserUpdateDTO
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, editURL)
+		return
+	}
+
+	input.Email = strings.ToLower(strings.TrimSpace(input.Email))
+	errs := validateUserFields(c, user.ID, input.Email, user.Role, input.Role)
+	if me := utils.CurrentAdmin(c); me != nil && me.ID == user.ID {
+		if input.Role != user.Role {
+			errs = addError(errs, "Role", "You cannot change your own role")
+		}
+		if input.Status != models.UserStatusActive {
+			errs = addError(errs, "Status", "You cannot disable your own account")
+		}
+	}
+
+	wasActive := user.IsActive()
+	user.Name = input.Name
+	user.Email = input.Email
+	user.Role = input.Role
+	user.Status = input.Status
+
+	if errs != nil {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, editURL)
+		return
+	}
+
+	user.BeforeUpdate()
+	if _, err := config.DB.NewUpdate().Model(&user).Column("name", "email", "role", "status", "updated_at").WherePK().Exec(c); err != nil {
+		utils.FlashFailure(c, err, "Failed to update user")
+		utils.FlashInput(c, nil)
+		c.Redirect(http.StatusSeeOther, editURL)
+		return
+	}
+
+	// Disabled → kick the user out of every session
+	if wasActive && !user.IsActive() {
+		logoutUser(c, user.ID)
+	}
+
+	utils.FlashSuccess(c, "User updated successfully!")
+	c.Redirect(http.StatusSeeOther, "/admin/user-list")
+}
+
+// User password change
+func AdminUpdateUserPassword(c *gin.Context) {
+	var user models.User
+	if err := config.DB.NewSelect().Model(&user).Where("id = ?", c.Param("id")).Scan(c); err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "User Not Found"))
+		return
+	}
+
+	if !canManageUser(c, &user) {
+		utils.Fail(c, utils.Forbidden(superAdminOnlyMsg))
+		return
+	}
+
+	editURL := "/admin/user-edit/" + c.Param("id")
+
+	var input dto.UserPasswordDTO
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, editURL)
+		return
+	}
+	if errs := utils.PasswordStrengthErrors(input.Password); errs != nil {
+		utils.FlashInput(c, errs)
+		c.Redirect(http.StatusSeeOther, editURL)
+		return
+	}
+
+	hashed, err := models.HashPassword(input.Password)
+	if err != nil {
+		utils.FlashFailure(c, err, "Failed to hash password")
+		c.Redirect(http.StatusSeeOther, editURL)
+		return
+	}
+
+	user.Password = hashed
+	user.BeforeUpdate()
+	if _, err := config.DB.NewUpdate().Model(&user).Column("password", "updated_at").WherePK().Exec(c); err != nil {
+		utils.FlashFailure(c, err, "Failed to update password")
+		c.Redirect(http.StatusSeeOther, editURL)
+		return
+	}
+
+	// Sessions opened with the old password are no longer trusted,
+	// except the one making this change
+	if me := utils.CurrentAdmin(c); me == nil || me.ID != user.ID {
+		logoutUser(c, user.ID)
+	}
+
+	utils.FlashSuccess(c, "Password updated successfully!")
+	c.Redirect(http.StatusSeeOther, editURL)
+}
+
+// Enable / disable (AJAX)
+func AdminToggleUserStatus(c *gin.Context) {
+	var user models.User
+	if err := config.DB.NewSelect().Model(&user).Where("id = ?", c.Param("id")).Scan(c); err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "User not found"))
+		return
+	}
+
+	if !canManageUser(c, &user) {
+		utils.Fail(c, utils.Forbidden(superAdminOnlyMsg))
+		return
+	}
+
+	if me := utils.CurrentAdmin(c); me != nil && me.ID == user.ID {
+		utils.Fail(c, utils.Validation("You cannot disable your own account", nil))
+		return
+	}
+
+	user.Status = 1 - user.Status
+	user.BeforeUpdate()
+	if _, err := config.DB.NewUpdate().Model(&user).Column("status", "updated_at").WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to update user status"))
+		return
+	}
+
+	if !user.IsActive() {
+		logoutUser(c, user.ID)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User status updated successfully", "status": user.Status})
+}
+
+// User delete
+func AdminDeleteUser(c *gin.Context) {
+	var user models.User
+	if err := config.DB.NewSelect().Model(&user).Where("id = ?", c.Param("id")).Scan(c); err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "User not found"))
+		return
+	}
+
+	if !canManageUser(c, &user) {
+		utils.Fail(c, utils.Forbidden(superAdminOnlyMsg))
+		return
+	}
+
+	if me := utils.CurrentAdmin(c); me != nil && me.ID == user.ID {
+		utils.Fail(c, utils.Validation("You cannot delete your own account", nil))
+		return
+	}
+
+	if _, err := config.DB.NewDelete().Model(&user).WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to delete user"))
+		return
+	}
+
+	logoutUser(c, user.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
+
+// validateUserFields checks the email is free (ignoring userID), the role exists
+// and the current admin may change oldRole to role (new users start as customers)
+func validateUserFields(c *gin.Context, userID int64, email, oldRole, role string) map[string]string {
+	var errs map[string]string
+
+	taken, _ := config.DB.NewSelect().Model((*models.User)(nil)).
+		Where("email = ?", email).
+		Where("id != ?", userID).
+		Exists(c)
+	if taken {
+		errs = addError(errs, "Email", "This email is already in use")
+	}
+
+	if !roleExists(c, role) {
+		errs = addError(errs, "Role", "Unknown role")
+	} else if msg := roleChangeError(c, oldRole, role); msg != "" {
+		errs = addError(errs, "Role", msg)
+	}
+	return errs
+}
+
+const superAdminOnlyMsg = "Only a super admin can change super admin accounts"
+
+// isSuperAdmin reports whether the logged in admin has the super admin role
+func isSuperAdmin(c *gin.Context) bool {
+	me := utils.CurrentAdmin(c)
+	return me != nil && me.Role == models.RoleAdmin
+}
+
+// canManageUser keeps user.update/user.delete holders away from super admin
+// accounts: editing, disabling or resetting the password of one would hand
+// them every permission
+func canManageUser(c *gin.Context, user *models.User) bool {
+	return user.Role != models.RoleAdmin || isSuperAdmin(c)
+}
+
+// roleChangeError returns why the current admin may not change oldRole to
+// role, or "". Assigning roles needs role.manage; granting or revoking the
+// super admin role needs a super admin.
+func roleChangeError(c *gin.Context, oldRole, role string) string {
+	if role == oldRole {
+		return ""
+	}
+	if !utils.Can(c, "role.manage") {
+		return "You do not have permission to assign roles"
+	}
+	if (role == models.RoleAdmin || oldRole == models.RoleAdmin) && !isSuperAdmin(c) {
+		return "Only a super admin can grant or revoke the super admin role"
+	}
+	return ""
+}
+
+func addError(errs map[string]string, field, msg string) map[string]string {
+	if errs == nil {
+		errs = map[string]string{}
+	}
+	errs[field] = msg
+	return errs
+}
+
+// roleExists accepts the built-in admin role even before db:seed has run
+func roleExists(c *gin.Context, slug string) bool {
+	if slug == models.RoleAdmin {
+		return true
+	}
+	exists, _ := config.DB.NewSelect().Model((*models.Role)(nil)).Where("slug = ?", slug).Exists(c)
+	return exists
+}
+
+func loadRoles(c *gin.Context) []models.Role {
+	var roles []models.Role
+	_ = config.DB.NewSelect().Model(&roles).Order("id ASC").Scan(c)
+	return roles
+}
+
+// logoutUser revokes every admin and API session of the user
+func logoutUser(c *gin.Context, userID int64) {
+	if err := utils.RevokeUserTokens(c.Request.Context(), userID); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Failed to revoke sessions", "user_id", userID, "error", err)
+	}
+}
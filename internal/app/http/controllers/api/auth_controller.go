@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"errors"
+	"gin-app/config"
+	"gin-app/internal/dto"
+	"gin-app/internal/models"
+	"gin-app/internal/pkg/metrics"
+	"gin-app/internal/utils"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /api/v1/auth/login
+func AuthLogin(c *gin.Context) {
+	var input dto.ApiLoginDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "Validation failed", errs)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := utils.CheckLoginAllowed(ctx, c.ClientIP(), input.Email); err != nil {
+		var limitErr *utils.LoginLimitError
+		if errors.As(err, &limitErr) {
+			metrics.Login(utils.ApiScope.Name, metrics.LoginThrottled)
+			c.Header("Retry-After", strconv.Itoa(int(limitErr.RetryAfter.Seconds())+1))
+			utils.RespondError(c, http.StatusTooManyRequests, limitErr.Message(), nil)
+			return
+		}
+		slog.ErrorContext(c.Request.Context(), "Login limiter unavailable", "error", err)
+	}
+
+	user, err := models.GetUserByEmail(ctx, config.DB, input.Email)
+	if err != nil || !models.CheckPassword(input.Password, user.Password) {
+		metrics.Login(utils.ApiScope.Name, metrics.LoginFailure)
+		if err := utils.RegisterLoginFailure(ctx, c.ClientIP(), input.Email); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to record login failure", "error", err)
+		}
+		utils.RespondError(c, http.StatusUnauthorized, "Invalid email or password", nil)
+		return
+	}
+	if user.TwoFactorEnabled() {
+		if input.Code == "" {
+			utils.RespondError(c, http.StatusUnauthorized, "Two-factor code required", map[string]string{"Code": "Authentication code is required"})
+			return
+		}
+		if !utils.VerifySecondFactor(ctx, user, input.Code) {
+			metrics.Login(utils.ApiScope.Name, metrics.LoginFailure)
+			if err := utils.RegisterLoginFailure(ctx, c.ClientIP(), input.Email); err != nil {
+				slog.ErrorContext(c.Request.Context(), "Failed to record login failure", "error", err)
+			}
+			utils.RespondError(c, http.StatusUnauthorized, "Invalid two-factor code", nil)
+			return
+		}
+	}
+	if !user.IsActive() {
+		metrics.Login(utils.ApiScope.Name, metrics.LoginDenied)
+		utils.RespondError(c, http.StatusForbidden, "Your account has been disabled", nil)
+		return
+	}
+
+	pair, err := utils.CreateSession(c.Request.Context(), utils.ApiScope, user, utils.SessionMetaFrom(c), utils.AccessTTL(), utils.RefreshTTL())
+	if err != nil {
+		utils.RespondError(c, http.StatusInternalServerError, "Could not issue tokens", nil)
+		return
+	}
+	// Only a completed login clears the counters, not a correct password of a disabled account
+	utils.ResetLoginFailures(ctx, input.Email)
+
+	metrics.Login(utils.ApiScope.Name, metrics.LoginSuccess)
+	utils.RespondSuccess(c, http.StatusOK, "Logged in successfully", gin.H{
+		"user":   user,
+		"tokens": tokenResponse(pair),
+	})
+}
+
+// POST /api/v1/auth/refresh
+func AuthRefresh(c *gin.Context) {
+	var input dto.ApiRefreshDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "Validation failed", errs)
+		return
+	}
+
+	ref, pair, err := utils.RotateRefreshToken(c.Request.Context(), utils.ApiScope, input.RefreshToken, "")
+	if errors.Is(err, utils.ErrRefreshTokenReused) {
+		slog.WarnContext(c.Request.Context(), "API refresh token reuse detected, all sessions revoked", "user_id", ref.UserID)
+		utils.RespondError(c, http.StatusUnauthorized, "Refresh token reuse detected, please log in again", nil)
+		return
+	}
+	if err != nil || pair == nil {
+		utils.RespondError(c, http.StatusUnauthorized, "Invalid or expired refresh token", nil)
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Token refreshed successfully", gin.H{
+		"tokens": tokenResponse(pair),
+	})
+}
+
+// POST /api/v1/auth/logout
+func AuthLogout(c *gin.Context) {
+	var input dto.ApiLogoutDTO
+	_ = c.ShouldBind(&input) // refresh_token is optional
+
+	utils.RevokeTokens(c.Request.Context(), utils.ApiScope, c.GetString(utils.ApiTokenContextKey), input.RefreshToken)
+
+	utils.RespondSuccess(c, http.StatusOK, "Logged out successfully", nil)
+}
+
+// GET /api/v1/auth/me
+func AuthMe(c *gin.Context) {
+	utils.RespondSuccess(c, http.StatusOK, "Authenticated user", utils.CurrentApiUser(c))
+}
+
+func tokenResponse(pair *utils.TokenPair) gin.H {
+	return gin.H{
+		"token_type":         "Bearer",
+		"access_token":       pair.AccessToken,
+		"expires_in":         int(pair.AccessTTL.Seconds()),
+		"refresh_token":      pair.RefreshToken,
+		"refresh_expires_in": int(pair.RefreshTTL.Seconds()),
+	}
+}
@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"gin-app/config"
+	"gin-app/internal/dto"
+	"gin-app/internal/models"
+	"gin-app/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/v1/categories
+func CategoryIndex(c *gin.Context) {
+	// Filters
+	search := c.Query("search")
+	status := c.Query("status")
+	createdAt := c.Query("created_at")
+
+	// Cursor pagination
+	lastID, limit := utils.GetCursorPagination(c)
+
+	// Base query
+	query := config.DB.NewSelect().Model((*models.Category)(nil))
+
+	if search != "" {
+		query = query.Where("name ILIKE ? OR slug ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if createdAt != "" {
+		query = query.Where("DATE(created_at) = ?", createdAt)
+	}
+
+	// Count before the cursor condition so total covers every page
+	totalCount, err := query.Count(c)
+	if err != nil {
+		totalCount = 0
+	}
+
+	// Cursor condition
+	if lastID > 0 {
+		query = query.Where("id > ?", lastID)
+	}
+
+	var categories []models.Category
+	if err := query.Order("id ASC").Limit(limit).Scan(c, &categories); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch categories"))
+		return
+	}
+
+	var nextCursor int64
+	if len(categories) > 0 {
+		nextCursor = categories[len(categories)-1].ID
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Categories fetched successfully", categories, cursorMeta(len(categories), limit, totalCount, nextCursor))
+}
+
+// GET /api/v1/categories/:id
+func CategoryShow(c *gin.Context) {
+	category, ok := findCategory(c)
+	if !ok {
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, "Category fetched successfully", category)
+}
+
+// POST /api/v1/categories
+func CategoryStore(c *gin.Context) {
+	var input dto.CategoryStoreDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "Validation failed", errs)
+		return
+	}
+
+	slug := utils.MakeSlug(input.Name)
+	if slug == "" {
+		slug = "not-available"
+	}
+
+	category := models.Category{
+		Name:      input.Name,
+		Slug:      slug,
+		Status:    input.Status,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if _, err := config.DB.NewInsert().Model(&category).Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to create category"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, "Category created successfully", category)
+}
+
+// PUT /api/v1/categories/:id
+func CategoryUpdate(c *gin.Context) {
+	var input dto.CategoryUpdateDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "Validation failed", errs)
+		return
+	}
+
+	category, ok := findCategory(c)
+	if !ok {
+		return
+	}
+
+	category.Name = input.Name
+	category.Slug = utils.MakeSlug(input.Name)
+	category.Status = input.Status
+	category.UpdatedAt = time.Now()
+
+	if _, err := config.DB.NewUpdate().Model(category).WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to update category"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Category updated successfully", category)
+}
+
+// DELETE /api/v1/categories/:id
+func CategoryDelete(c *gin.Context) {
+	category, ok := findCategory(c)
+	if !ok {
+		return
+	}
+
+	if _, err := config.DB.NewDelete().Model(category).WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to delete category"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Category deleted successfully", nil)
+}
+
+// PATCH /api/v1/categories/:id/status
+func CategoryToggleStatus(c *gin.Context) {
+	category, ok := findCategory(c)
+	if !ok {
+		return
+	}
+
+	category.Status = 1 - category.Status
+	category.UpdatedAt = time.Now()
+
+	if _, err := config.DB.NewUpdate().Model(category).Column("status", "updated_at").WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to update category status"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Category status updated successfully", category)
+}
+
+// findCategory loads the category from the :id param and fails with 404/500 itself
+func findCategory(c *gin.Context) (*models.Category, bool) {
+	id, ok := parseID(c)
+	if !ok {
+		return nil, false
+	}
+
+	var category models.Category
+	err := config.DB.NewSelect().Model(&category).Where("id = ?", id).Scan(c)
+	if err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Category not found"))
+		return nil, false
+	}
+	return &category, true
+}
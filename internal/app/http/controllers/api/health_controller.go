@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"gin-app/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /healthz
+//
+// Liveness: the process is up and serving requests. Does not touch any
+// dependency, so a database outage does not get the container restarted.
+func Healthz(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"status": utils.HealthOK})
+}
+
+// GET /readyz
+//
+// Readiness for the load balancer: Postgres, Redis and pending migrations,
+// each with its latency. 503 while any check fails.
+func Readyz(c *gin.Context) {
+	checks, ready := utils.Readiness(c.Request.Context())
+
+	status, code := utils.HealthOK, http.StatusOK
+	if !ready {
+		status, code = "unavailable", http.StatusServiceUnavailable
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(code, gin.H{"status": status, "checks": checks})
+}
@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"gin-app/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseID reads the :id route param and writes a 400 when it isn't a number
+func parseID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		utils.RespondError(c, http.StatusBadRequest, "Invalid id", nil)
+		return 0, false
+	}
+	return id, true
+}
+
+// cursorMeta builds the pagination meta for list endpoints
+func cursorMeta(count, limit, total int, lastID int64) gin.H {
+	meta := gin.H{
+		"limit":       limit,
+		"total":       total,
+		"has_more":    count == limit,
+		"next_cursor": nil,
+	}
+	if count == limit {
+		meta["next_cursor"] = lastID
+	}
+	return meta
+}
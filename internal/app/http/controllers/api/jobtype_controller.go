@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"gin-app/config"
+	"gin-app/internal/dto"
+	"gin-app/internal/models"
+	"gin-app/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/v1/job-types
+func JobTypeIndex(c *gin.Context) {
+	// Filters
+	search := c.Query("search")
+	status := c.Query("status")
+	createdAt := c.Query("created_at")
+
+	// Cursor pagination
+	lastID, limit := utils.GetCursorPagination(c)
+
+	// Base query
+	query := config.DB.NewSelect().Model((*models.JobType)(nil))
+
+	if search != "" {
+		query = query.Where("name ILIKE ? OR slug ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if createdAt != "" {
+		query = query.Where("DATE(created_at) = ?", createdAt)
+	}
+
+	// Count before the cursor condition so total covers every page
+	totalCount, err := query.Count(c)
+	if err != nil {
+		totalCount = 0
+	}
+
+	// Cursor condition
+	if lastID > 0 {
+		query = query.Where("id > ?", lastID)
+	}
+
+	var jobs []models.JobType
+	if err := query.Order("id ASC").Limit(limit).Scan(c, &jobs); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch job types"))
+		return
+	}
+
+	var nextCursor int64
+	if len(jobs) > 0 {
+		nextCursor = jobs[len(jobs)-1].ID
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Job types fetched successfully", jobs, cursorMeta(len(jobs), limit, totalCount, nextCursor))
+}
+
+// GET /api/v1/job-types/:id
+func JobTypeShow(c *gin.Context) {
+	job, ok := findJobType(c)
+	if !ok {
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, "Job type fetched successfully", job)
+}
+
+// POST /api/v1/job-types
+func JobTypeStore(c *gin.Context) {
+	var input dto.JobTypeStoreDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "Validation failed", errs)
+		return
+	}
+
+	slug := utils.MakeSlug(input.Name)
+	if slug == "" {
+		slug = "not-available"
+	}
+
+	job := models.JobType{
+		Name:      input.Name,
+		Slug:      slug,
+		Status:    input.Status,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if _, err := config.DB.NewInsert().Model(&job).Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to create job type"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, "Job type created successfully", job)
+}
+
+// PUT /api/v1/job-types/:id
+func JobTypeUpdate(c *gin.Context) {
+	var input dto.JobTypeUpdateDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "Validation failed", errs)
+		return
+	}
+
+	job, ok := findJobType(c)
+	if !ok {
+		return
+	}
+
+	job.Name = input.Name
+	job.Slug = utils.MakeSlug(input.Name)
+	job.Status = input.Status
+	job.UpdatedAt = time.Now()
+
+	if _, err := config.DB.NewUpdate().Model(job).WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to update job type"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Job type updated successfully", job)
+}
+
+// DELETE /api/v1/job-types/:id
+func JobTypeDelete(c *gin.Context) {
+	job, ok := findJobType(c)
+	if !ok {
+		return
+	}
+
+	if _, err := config.DB.NewDelete().Model(job).WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to delete job type"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Job type deleted successfully", nil)
+}
+
+// PATCH /api/v1/job-types/:id/status
+func JobTypeToggleStatus(c *gin.Context) {
+	job, ok := findJobType(c)
+	if !ok {
+		return
+	}
+
+	job.Status = 1 - job.Status
+	job.UpdatedAt = time.Now()
+
+	if _, err := config.DB.NewUpdate().Model(job).Column("status", "updated_at").WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to update job type status"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Job type status updated successfully", job)
+}
+
+// findJobType loads the job type from the :id param and fails with 404/500 itself
+func findJobType(c *gin.Context) (*models.JobType, bool) {
+	id, ok := parseID(c)
+	if !ok {
+		return nil, false
+	}
+
+	var job models.JobType
+	err := config.DB.NewSelect().Model(&job).Where("id = ?", id).Scan(c)
+	if err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Job type not found"))
+		return nil, false
+	}
+	return &job, true
+}
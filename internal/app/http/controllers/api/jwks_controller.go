@@ -0,0 +1,17 @@
+package controllers
+
+import (
+	"gin-app/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /.well-known/jwks.json
+//
+// Public keys other services use to verify our tokens. Plain JWK Set, not
+// wrapped in the API response envelope.
+func Jwks(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, utils.JWKS())
+}
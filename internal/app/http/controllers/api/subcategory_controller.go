@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"gin-app/config"
+	"gin-app/internal/dto"
+	"gin-app/internal/models"
+	"gin-app/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /api/v1/subcategories
+func SubcategoryIndex(c *gin.Context) {
+	// Filters
+	search := c.Query("search")
+	status := c.Query("status")
+	categoryID := c.Query("category_id")
+	createdAt := c.Query("created_at")
+
+	// Cursor pagination
+	lastID, limit := utils.GetCursorPagination(c)
+
+	// Base query
+	query := config.DB.NewSelect().
+		Model((*models.Subcategory)(nil)).
+		Relation("Category") // join
+
+	if search != "" {
+		query = query.Where("subcategory.name ILIKE ? OR subcategory.slug ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+	if status != "" {
+		query = query.Where("subcategory.status = ?", status)
+	}
+	if categoryID != "" {
+		query = query.Where("subcategory.category_id = ?", categoryID)
+	}
+	if createdAt != "" {
+		query = query.Where("DATE(subcategory.created_at) = ?", createdAt)
+	}
+
+	// Count before the cursor condition so total covers every page
+	totalCount, err := query.Count(c)
+	if err != nil {
+		totalCount = 0
+	}
+
+	// Cursor condition
+	if lastID > 0 {
+		query = query.Where("subcategory.id > ?", lastID)
+	}
+
+	var data []models.Subcategory
+	if err := query.Order("subcategory.id ASC").Limit(limit).Scan(c, &data); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch subcategories"))
+		return
+	}
+
+	var nextCursor int64
+	if len(data) > 0 {
+		nextCursor = data[len(data)-1].ID
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Subcategories fetched successfully", data, cursorMeta(len(data), limit, totalCount, nextCursor))
+}
+
+// GET /api/v1/subcategories/:id
+func SubcategoryShow(c *gin.Context) {
+	subcategory, ok := findSubcategory(c)
+	if !ok {
+		return
+	}
+	utils.RespondSuccess(c, http.StatusOK, "Subcategory fetched successfully", subcategory)
+}
+
+// POST /api/v1/subcategories
+func SubcategoryStore(c *gin.Context) {
+	var input dto.SubcategoryStoreDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "Validation failed", errs)
+		return
+	}
+	if !categoryExists(c, input.CategoryID) {
+		return
+	}
+
+	slug := utils.MakeSlug(input.Name)
+	if slug == "" {
+		slug = "not-available"
+	}
+
+	subcategory := models.Subcategory{
+		Name:       input.Name,
+		CategoryID: input.CategoryID,
+		Slug:       slug,
+		Status:     input.Status,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	if _, err := config.DB.NewInsert().Model(&subcategory).Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to create subcategory"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusCreated, "Subcategory created successfully", subcategory)
+}
+
+// PUT /api/v1/subcategories/:id
+func SubcategoryUpdate(c *gin.Context) {
+	var input dto.SubcategoryUpdateDTO
+
+	if valid, errs := utils.ValidateStruct(c, &input); !valid {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "Validation failed", errs)
+		return
+	}
+
+	subcategory, ok := findSubcategory(c)
+	if !ok {
+		return
+	}
+	if !categoryExists(c, input.CategoryID) {
+		return
+	}
+
+	subcategory.Name = input.Name
+	subcategory.Slug = utils.MakeSlug(input.Name)
+	subcategory.CategoryID = input.CategoryID
+	subcategory.Status = input.Status
+	subcategory.UpdatedAt = time.Now()
+	subcategory.Category = nil
+
+	if _, err := config.DB.NewUpdate().Model(subcategory).WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to update subcategory"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Subcategory updated successfully", subcategory)
+}
+
+// DELETE /api/v1/subcategories/:id
+func SubcategoryDelete(c *gin.Context) {
+	subcategory, ok := findSubcategory(c)
+	if !ok {
+		return
+	}
+
+	if _, err := config.DB.NewDelete().Model(subcategory).WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to delete subcategory"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Subcategory deleted successfully", nil)
+}
+
+// PATCH /api/v1/subcategories/:id/status
+func SubcategoryToggleStatus(c *gin.Context) {
+	subcategory, ok := findSubcategory(c)
+	if !ok {
+		return
+	}
+
+	subcategory.Status = 1 - subcategory.Status
+	subcategory.UpdatedAt = time.Now()
+
+	if _, err := config.DB.NewUpdate().Model(subcategory).Column("status", "updated_at").WherePK().Exec(c); err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to update subcategory status"))
+		return
+	}
+
+	utils.RespondSuccess(c, http.StatusOK, "Subcategory status updated successfully", subcategory)
+}
+
+// findSubcategory loads the subcategory (with its category) from the :id param and fails with 404/500 itself
+func findSubcategory(c *gin.Context) (*models.Subcategory, bool) {
+	id, ok := parseID(c)
+	if !ok {
+		return nil, false
+	}
+
+	var subcategory models.Subcategory
+	err := config.DB.NewSelect().Model(&subcategory).Relation("Category").Where("subcategory.id = ?", id).Scan(c)
+	if err != nil {
+		utils.Fail(c, utils.NotFoundOrInternal(err, "Subcategory not found"))
+		return nil, false
+	}
+	return &subcategory, true
+}
+
+// categoryExists writes a validation error when the category is unknown
+func categoryExists(c *gin.Context, categoryID int) bool {
+	exists, err := config.DB.NewSelect().Model((*models.Category)(nil)).Where("id = ?", categoryID).Exists(c)
+	if err != nil {
+		utils.Fail(c, utils.Internal(err, "Failed to fetch category"))
+		return false
+	}
+	if !exists {
+		utils.RespondError(c, http.StatusUnprocessableEntity, "Validation failed", map[string]string{
+			"CategoryID": "Selected category does not exist",
+		})
+		return false
+	}
+	return true
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"gin-app/config"
+	"gin-app/internal/models"
+	"gin-app/internal/utils"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,16 +11,47 @@ import (
 
 func AdminAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token, err := c.Cookie("admin_access")
-		if err != nil || token == "" {
+		var session utils.SessionRef
+
+		// Check Redis
+		if token, _ := c.Cookie("admin_access"); token != "" {
+			session, _ = utils.LookupAdminAccessToken(c.Request.Context(), token)
+		}
+
+		// Access token expired → silently rotate with the refresh token
+		if session.UserID == 0 {
+			refreshToken, _ := c.Cookie("admin_refresh")
+			if refreshToken == "" {
+				c.Redirect(http.StatusSeeOther, "/admin/login")
+				c.Abort()
+				return
+			}
+
+			ref, err := utils.RotateAdminRefreshToken(c, refreshToken)
+			if err != nil {
+				utils.ClearAdminCookies(c)
+				c.Redirect(http.StatusSeeOther, "/admin/login")
+				c.Abort()
+				return
+			}
+			session = ref
+		}
+
+		admin, err := models.GetUserByID(c.Request.Context(), config.DB, session.UserID)
+		if err != nil || !admin.IsActive() {
+			utils.ClearAdminCookies(c)
 			c.Redirect(http.StatusSeeOther, "/admin/login")
 			c.Abort()
 			return
 		}
 
-		// Check Redis
-		_, err = config.RedisClient.Get(config.Ctx, "admin_access:"+token).Result()
-		if err != nil {
+		utils.SetLoggedInAdmin(c, admin)
+		c.Set(utils.AdminSessionContextKey, session.ID)
+		utils.TouchSession(c.Request.Context(), utils.AdminScope, session.ID, c.ClientIP())
+
+		// Customers and other roles without panel access
+		if !utils.Can(c, models.PermissionAdminAccess) {
+			utils.ClearAdminCookies(c)
 			c.Redirect(http.StatusSeeOther, "/admin/login")
 			c.Abort()
 			return
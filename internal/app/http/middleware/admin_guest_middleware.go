@@ -11,7 +11,7 @@ func AdminGuestMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token, _ := c.Cookie("admin_access")
 		if token != "" {
-			_, err := config.RedisClient.Get(config.Ctx, "admin_access:"+token).Result()
+			_, err := config.RedisClient.Get(c.Request.Context(), "admin_access:"+token).Result()
 			if err == nil {
 				c.Redirect(http.StatusSeeOther, "/admin/dashboard")
 				c.Abort()
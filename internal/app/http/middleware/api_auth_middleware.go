@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"errors"
+	"gin-app/config"
+	"gin-app/internal/models"
+	"gin-app/internal/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApiAuthMiddleware authenticates /api/v1 requests with an "Authorization: Bearer <token>" header
+func ApiAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			utils.AbortWithError(c, http.StatusUnauthorized, "Missing bearer token")
+			return
+		}
+
+		// Signature, expiry, issuer, API audience, access type + Redis allow-list (logout / revocation)
+		session, err := utils.LookupAccessToken(c.Request.Context(), utils.ApiScope, token)
+		if errors.Is(err, utils.ErrTokenRevoked) {
+			utils.AbortWithError(c, http.StatusUnauthorized, "Token has been revoked")
+			return
+		}
+		if err != nil {
+			utils.AbortWithError(c, http.StatusUnauthorized, "Invalid or expired token")
+			return
+		}
+
+		user, err := models.GetUserByID(c.Request.Context(), config.DB, session.UserID)
+		if err != nil {
+			utils.AbortWithError(c, http.StatusUnauthorized, "User not found")
+			return
+		}
+		if !user.IsActive() {
+			utils.AbortWithError(c, http.StatusForbidden, "Your account has been disabled")
+			return
+		}
+
+		c.Set(utils.ApiUserContextKey, user)
+		c.Set(utils.ApiTokenContextKey, token)
+		c.Set(utils.ApiSessionContextKey, session.ID)
+		utils.TouchSession(c.Request.Context(), utils.ApiScope, session.ID, c.ClientIP())
+		c.Next()
+	}
+}
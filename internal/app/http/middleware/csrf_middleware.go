@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"gin-app/internal/utils"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CsrfMiddleware issues the CSRF token of the browser and rejects unsafe
+// requests (POST, PUT, PATCH, DELETE) that don't send it back
+func CsrfMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := utils.EnsureCsrfToken(c); err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to issue CSRF token", "error", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+
+		if utils.CsrfSafeMethod(c.Request.Method) || utils.ValidCsrfToken(c) {
+			c.Next()
+			return
+		}
+
+		const message = "Your session has expired or the request was not sent from this site. Reload the page and try again."
+		if c.GetHeader(utils.CsrfHeader) != "" || utils.WantsJSON(c) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": message})
+			return
+		}
+
+		utils.HTML(c, http.StatusForbidden, "403.html", gin.H{
+			"title":   "Forbidden",
+			"message": message,
+		})
+		c.Abort()
+	}
+}
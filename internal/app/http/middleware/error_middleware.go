@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"gin-app/internal/pkg/logger"
+	"gin-app/internal/utils"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler renders the error a handler passed to utils.Fail (or c.Error)
+// when the handler wrote no response: JSON for the API and AJAX calls, an
+// error page otherwise. Only AppError messages reach the client; causes are
+// logged by RequestLogger.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		RenderError(c, c.Errors.Last().Err)
+	}
+}
+
+// Recovery turns a panic into a logged 500 response instead of a dropped connection
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			// The client went away, nothing to answer
+			if err, ok := recovered.(error); ok && (errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)) {
+				c.Abort()
+				return
+			}
+
+			slog.ErrorContext(c.Request.Context(), "Panic recovered",
+				"panic", fmt.Sprint(recovered),
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"stack", string(debug.Stack()),
+			)
+
+			err := utils.AsAppError(fmt.Errorf("panic: %v", recovered))
+			_ = c.Error(err)
+			if !c.Writer.Written() {
+				RenderError(c, err)
+			}
+			c.Abort()
+		}()
+		c.Next()
+	}
+}
+
+// Headings of the error page per status
+var errorHeadings = map[int]string{
+	http.StatusUnauthorized:        "Unauthorized",
+	http.StatusConflict:            "Conflict",
+	http.StatusUnprocessableEntity: "Invalid Request",
+}
+
+// RenderError writes err as JSON or as an error page
+func RenderError(c *gin.Context, err error) {
+	appErr := utils.AsAppError(err)
+	status := appErr.Status()
+
+	if utils.WantsJSON(c) {
+		if strings.HasPrefix(c.Request.URL.Path, "/api/") {
+			utils.RespondError(c, status, appErr.Message, appErr.Fields)
+		} else {
+			body := gin.H{"error": appErr.Message}
+			if len(appErr.Fields) > 0 {
+				body["errors"] = appErr.Fields
+			}
+			c.JSON(status, body)
+		}
+		c.Abort()
+		return
+	}
+
+	if status == http.StatusNotFound {
+		utils.HTML(c, status, "404.html", gin.H{"title": "Page Not Found"})
+		c.Abort()
+		return
+	}
+	if status == http.StatusForbidden {
+		utils.HTML(c, status, "403.html", gin.H{"title": "Forbidden", "message": appErr.Message})
+		c.Abort()
+		return
+	}
+
+	data := gin.H{
+		"title":   appErr.Message,
+		"status":  status,
+		"heading": errorHeadings[status],
+		"message": appErr.Message,
+	}
+	if status >= http.StatusInternalServerError {
+		data["request_id"] = c.GetString(logger.RequestIDKey) // for bug reports
+	}
+	utils.HTML(c, status, "500.html", data)
+	c.Abort()
+}
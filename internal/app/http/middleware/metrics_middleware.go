@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"gin-app/config"
+	"gin-app/internal/pkg/metrics"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics counts requests and observes their latency per route template
+// (/admin/users/:id, not /admin/users/42, to keep the label set small)
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched" // 404s, any path
+		}
+		method := c.Request.Method
+
+		metrics.HTTPRequests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// MetricsToken protects /metrics on the main server with metrics.token
+// (Authorization: Bearer <token>); without a token the endpoint is open
+func MetricsToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := config.AppConfig.Metrics.Token
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="metrics"`)
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"gin-app/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission aborts with 403 unless the authenticated user's role grants the permission.
+// Use it after AdminAuthMiddleware or ApiAuthMiddleware, e.g. RequirePermission("category.delete").
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if utils.Can(c, permission) {
+			c.Next()
+			return
+		}
+
+		if utils.WantsJSON(c) {
+			utils.AbortWithError(c, http.StatusForbidden, "You do not have permission to perform this action")
+			return
+		}
+
+		utils.HTML(c, http.StatusForbidden, "403.html", gin.H{
+			"title": "Forbidden",
+		})
+		c.Abort()
+	}
+}
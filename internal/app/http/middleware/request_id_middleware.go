@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"gin-app/internal/pkg/logger"
+	"gin-app/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// Longest X-Request-ID accepted from proxies
+const maxRequestIDLen = 128
+
+// RequestID keeps the X-Request-ID of the proxy (or generates one), returns it
+// in the response and puts it into the request context, so every log record
+// of the request (HTTP, SQL, Redis) carries it
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id, _ = utils.RandomToken(16)
+		}
+
+		c.Set(logger.RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("http.request_id", id))
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// validRequestID rejects IDs that could forge log lines or bloat them
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"gin-app/config"
+	"gin-app/internal/pkg/sessionstore"
+	"gin-app/internal/utils"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+// SessionMiddleware gives admin requests a Redis backed session (flash
+// messages); Redis is only queried when a handler or template uses it
+func SessionMiddleware() gin.HandlerFunc {
+	conf := config.AppConfig.Cookie
+
+	store := sessionstore.NewRedisStore(config.RedisClient, utils.SessionPrefix, sessions.Options{
+		Path:     "/",
+		Domain:   conf.Domain,
+		MaxAge:   int(config.AppConfig.App.SessionTTL.Seconds()),
+		Secure:   conf.Secure,
+		HttpOnly: true,
+		SameSite: utils.CookieSameSite(conf.SameSite),
+	})
+	return sessions.Sessions(utils.SessionCookie, store)
+}
@@ -0,0 +1,33 @@
+package dto
+
+type ForgetPasswordDTO struct {
+	Email string `form:"email" binding:"required,email"`
+}
+
+type ResetPasswordDTO struct {
+	Token                string `form:"token" binding:"required"`
+	Password             string `form:"password" binding:"required,min=12,max=72"`
+	PasswordConfirmation string `form:"password_confirmation" binding:"required,eqfield=Password"`
+}
+
+type ApiLoginDTO struct {
+	Email    string `form:"email" json:"email" binding:"required,email"`
+	Password string `form:"password" json:"password" binding:"required"`
+	Code     string `form:"code" json:"code"` // TOTP or recovery code, required when 2FA is on
+}
+
+type ApiRefreshDTO struct {
+	RefreshToken string `form:"refresh_token" json:"refresh_token" binding:"required"`
+}
+
+type ApiLogoutDTO struct {
+	RefreshToken string `form:"refresh_token" json:"refresh_token"`
+}
+
+type TwoFactorCodeDTO struct {
+	Code string `form:"code" json:"code" binding:"required"`
+}
+
+type TwoFactorDisableDTO struct {
+	Password string `form:"password" json:"password" binding:"required"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+type RoleStoreDTO struct {
+	Name          string  `form:"name" binding:"required,min=2,max=255"`
+	Description   string  `form:"description" binding:"max=255"`
+	PermissionIDs []int64 `form:"permissions"`
+}
+
+type RoleUpdateDTO struct {
+	Name          string  `form:"name" binding:"required,min=2,max=255"`
+	Description   string  `form:"description" binding:"max=255"`
+	PermissionIDs []int64 `form:"permissions"`
+}
+
+type UserRoleDTO struct {
+	Role string `form:"role" json:"role" binding:"required"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+type UserStoreDTO struct {
+	Name                 string `form:"name" json:"name" binding:"required,min=2,max=255"`
+	Email                string `form:"email" json:"email" binding:"required,email,max=255"`
+	Password             string `form:"password" json:"password" binding:"required,min=12,max=72"`
+	PasswordConfirmation string `form:"password_confirmation" json:"password_confirmation" binding:"required,eqfield=Password"`
+	Role                 string `form:"role" json:"role" binding:"required"`
+	Status               int    `form:"status" json:"status" binding:"oneof=0 1"`
+}
+
+type UserUpdateDTO struct {
+	Name   string `form:"name" json:"name" binding:"required,min=2,max=255"`
+	Email  string `form:"email" json:"email" binding:"required,email,max=255"`
+	Role   string `form:"role" json:"role" binding:"required"`
+	Status int    `form:"status" json:"status" binding:"oneof=0 1"`
+}
+
+type UserPasswordDTO struct {
+	Password             string `form:"password" json:"password" binding:"required,min=12,max=72"`
+	PasswordConfirmation string `form:"password_confirmation" json:"password_confirmation" binding:"required,eqfield=Password"`
+}
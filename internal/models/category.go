@@ -8,13 +8,13 @@ import (
 
 type Category struct {
 	bun.BaseModel `bun:"table:categories"`
-	ID            int64     `bun:"id,pk,autoincrement"`
-	Name          string    `bun:"name,notnull"`
-	Slug          string    `bun:"slug,notnull"`
-	Status        int       `bun:"status,notnull,default:1"`
-	CreatedAt     time.Time `bun:"created_at,default:now()"`
-	UpdatedAt     time.Time `bun:"updated_at,default:now()"`
+	ID            int64     `bun:"id,pk,autoincrement" json:"id"`
+	Name          string    `bun:"name,notnull" json:"name"`
+	Slug          string    `bun:"slug,notnull" json:"slug"`
+	Status        int       `bun:"status,notnull,default:1" json:"status"`
+	CreatedAt     time.Time `bun:"created_at,default:now()" json:"created_at"`
+	UpdatedAt     time.Time `bun:"updated_at,default:now()" json:"updated_at"`
 
 	// revers join optional
-	Subcategories []*Subcategory `bun:"rel:has-many,join:id=category_id"`
+	Subcategories []*Subcategory `bun:"rel:has-many,join:id=category_id" json:"subcategories,omitempty"`
 }
@@ -8,10 +8,10 @@ import (
 
 type JobType struct {
 	bun.BaseModel `bun:"table:job_types"`
-	ID            int64     `bun:"id,pk,autoincrement"`
-	Name          string    `bun:"name,notnull"`
-	Slug          string    `bun:"slug,notnull"`
-	Status        int       `bun:"status,notnull,default:1"`
-	CreatedAt     time.Time `bun:"created_at,default:now()"`
-	UpdatedAt     time.Time `bun:"updated_at,default:now()"`
+	ID            int64     `bun:"id,pk,autoincrement" json:"id"`
+	Name          string    `bun:"name,notnull" json:"name"`
+	Slug          string    `bun:"slug,notnull" json:"slug"`
+	Status        int       `bun:"status,notnull,default:1" json:"status"`
+	CreatedAt     time.Time `bun:"created_at,default:now()" json:"created_at"`
+	UpdatedAt     time.Time `bun:"updated_at,default:now()" json:"updated_at"`
 }
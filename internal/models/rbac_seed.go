@@ -0,0 +1,104 @@
+package models
+
+import (
+	"context"
+
+	"github.com/uptrace/bun"
+)
+
+// DefaultPermissions are created by SeedRBAC (slug → display name)
+var DefaultPermissions = []Permission{
+	{Slug: PermissionAdminAccess, Name: "Access admin panel"},
+
+	{Slug: "job_type.view", Name: "View job types"},
+	{Slug: "job_type.create", Name: "Create job types"},
+	{Slug: "job_type.update", Name: "Update job types"},
+	{Slug: "job_type.delete", Name: "Delete job types"},
+
+	{Slug: "category.view", Name: "View categories"},
+	{Slug: "category.create", Name: "Create categories"},
+	{Slug: "category.update", Name: "Update categories"},
+	{Slug: "category.delete", Name: "Delete categories"},
+
+	{Slug: "subcategory.view", Name: "View subcategories"},
+	{Slug: "subcategory.create", Name: "Create subcategories"},
+	{Slug: "subcategory.update", Name: "Update subcategories"},
+	{Slug: "subcategory.delete", Name: "Delete subcategories"},
+
+	{Slug: "user.view", Name: "View users"},
+	{Slug: "user.create", Name: "Create users"},
+	{Slug: "user.update", Name: "Update users, their passwords and status"},
+	{Slug: "user.delete", Name: "Delete users"},
+
+	{Slug: "role.manage", Name: "Manage roles and assign them to users"},
+}
+
+// DefaultRoles are created by SeedRBAC. The admin role needs no permission
+// rows because it bypasses every check.
+var DefaultRoles = []struct {
+	Role        Role
+	Permissions []string
+}{
+	{
+		Role: Role{Slug: RoleAdmin, Name: "Super Admin", Description: "Full access to everything"},
+	},
+	{
+		Role: Role{Slug: RoleEditor, Name: "Editor", Description: "Manages job types, categories and subcategories"},
+		Permissions: []string{
+			PermissionAdminAccess,
+			"job_type.view", "job_type.create", "job_type.update", "job_type.delete",
+			"category.view", "category.create", "category.update", "category.delete",
+			"subcategory.view", "subcategory.create", "subcategory.update", "subcategory.delete",
+		},
+	},
+	{
+		Role: Role{Slug: RoleCustomer, Name: "Customer", Description: "Frontend user without admin access"},
+	},
+}
+
+// SeedRBAC inserts the default permissions and roles. It is safe to run
+// repeatedly: existing roles and their grants are left untouched, so
+// permissions removed in the admin UI are not restored.
+func SeedRBAC(ctx context.Context, db *bun.DB) error {
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		perms := make([]Permission, len(DefaultPermissions))
+		copy(perms, DefaultPermissions)
+		_, err := tx.NewInsert().Model(&perms).
+			On("CONFLICT (slug) DO UPDATE").
+			Set("name = EXCLUDED.name").
+			Returning("id, slug").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		permIDs := make(map[string]int64, len(perms))
+		for _, p := range perms {
+			permIDs[p.Slug] = p.ID
+		}
+
+		for _, def := range DefaultRoles {
+			role := def.Role
+			res, err := tx.NewInsert().Model(&role).
+				On("CONFLICT (slug) DO NOTHING").
+				Returning("id").
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+			// An existing role keeps the permissions set in the admin UI;
+			// the defaults are only granted to a role created here
+			if n, _ := res.RowsAffected(); n == 0 {
+				continue
+			}
+
+			for _, slug := range def.Permissions {
+				grant := RolePermission{RoleID: role.ID, PermissionID: permIDs[slug]}
+				if _, err := tx.NewInsert().Model(&grant).On("CONFLICT DO NOTHING").Exec(ctx); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// RecoveryCodeCount is the number of one-time codes issued when 2FA is enabled
+const RecoveryCodeCount = 10
+
+// RecoveryCode is a one-time 2FA fallback code, only its hash is stored
+type RecoveryCode struct {
+	bun.BaseModel `bun:"table:user_recovery_codes"`
+	ID            int64     `bun:"id,pk,autoincrement"`
+	UserID        int64     `bun:"user_id,notnull"`
+	CodeHash      string    `bun:"code_hash,notnull"`
+	UsedAt        time.Time `bun:"used_at,nullzero"`
+	CreatedAt     time.Time `bun:"created_at,default:now()"`
+}
+
+// hashRecoveryCode normalizes the code ("abcd-efgh" == "ABCDEFGH") and hashes it.
+// The codes carry 40 random bits so a fast hash is enough.
+func hashRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateRecoveryCodes replaces the user's recovery codes and returns the new plain codes.
+// Pass a transaction, otherwise a failed insert leaves the user without codes.
+func GenerateRecoveryCodes(ctx context.Context, db bun.IDB, userID int64) ([]string, error) {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // 32 chars, no 0/O/1/I
+
+	codes := make([]string, RecoveryCodeCount)
+	rows := make([]RecoveryCode, RecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		for j := range b {
+			b[j] = alphabet[int(b[j])%len(alphabet)]
+		}
+		codes[i] = string(b[:4]) + "-" + string(b[4:])
+		rows[i] = RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(codes[i]), CreatedAt: time.Now()}
+	}
+
+	if _, err := db.NewDelete().Model((*RecoveryCode)(nil)).Where("user_id = ?", userID).Exec(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := db.NewInsert().Model(&rows).Exec(ctx); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// UseRecoveryCode marks an unused code as used, it reports false for unknown or used codes
+func UseRecoveryCode(ctx context.Context, db bun.IDB, userID int64, code string) (bool, error) {
+	res, err := db.NewUpdate().
+		Model((*RecoveryCode)(nil)).
+		Set("used_at = ?", time.Now()).
+		Where("user_id = ?", userID).
+		Where("code_hash = ?", hashRecoveryCode(code)).
+		Where("used_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes the user has left
+func CountUnusedRecoveryCodes(ctx context.Context, db bun.IDB, userID int64) (int, error) {
+	return db.NewSelect().
+		Model((*RecoveryCode)(nil)).
+		Where("user_id = ?", userID).
+		Where("used_at IS NULL").
+		Count(ctx)
+}
+
+// DeleteRecoveryCodes removes all recovery codes of the user
+func DeleteRecoveryCodes(ctx context.Context, db bun.IDB, userID int64) error {
+	_, err := db.NewDelete().Model((*RecoveryCode)(nil)).Where("user_id = ?", userID).Exec(ctx)
+	return err
+}
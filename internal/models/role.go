@@ -0,0 +1,106 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// Built-in role slugs
+const (
+	RoleAdmin    = "admin" // super admin, always has every permission
+	RoleEditor   = "editor"
+	RoleCustomer = "customer"
+)
+
+// PermissionAdminAccess is required to log in to the admin panel at all
+const PermissionAdminAccess = "admin.access"
+
+type Role struct {
+	bun.BaseModel `bun:"table:roles"`
+	ID            int64     `bun:"id,pk,autoincrement" json:"id"`
+	Name          string    `bun:"name,notnull" json:"name"`
+	Slug          string    `bun:"slug,unique,notnull" json:"slug"`
+	Description   string    `bun:"description,notnull" json:"description"`
+	CreatedAt     time.Time `bun:"created_at,default:now()" json:"created_at"`
+	UpdatedAt     time.Time `bun:"updated_at,default:now()" json:"updated_at"`
+}
+
+type Permission struct {
+	bun.BaseModel `bun:"table:permissions"`
+	ID            int64     `bun:"id,pk,autoincrement" json:"id"`
+	Name          string    `bun:"name,notnull" json:"name"`
+	Slug          string    `bun:"slug,unique,notnull" json:"slug"`
+	CreatedAt     time.Time `bun:"created_at,default:now()" json:"created_at"`
+	UpdatedAt     time.Time `bun:"updated_at,default:now()" json:"updated_at"`
+}
+
+type RolePermission struct {
+	bun.BaseModel `bun:"table:role_permissions"`
+	RoleID        int64 `bun:"role_id,pk"`
+	PermissionID  int64 `bun:"permission_id,pk"`
+}
+
+// IsSuperAdmin reports whether the role bypasses permission checks
+func (r Role) IsSuperAdmin() bool {
+	return r.Slug == RoleAdmin
+}
+
+// GetRolePermissionSlugs returns the permission slugs granted to a role
+func GetRolePermissionSlugs(ctx context.Context, db bun.IDB, roleSlug string) ([]string, error) {
+	var slugs []string
+	err := db.NewSelect().
+		Model((*Permission)(nil)).
+		Column("permission.slug").
+		Join("JOIN role_permissions AS rp ON rp.permission_id = permission.id").
+		Join("JOIN roles AS r ON r.id = rp.role_id").
+		Where("r.slug = ?", roleSlug).
+		Scan(ctx, &slugs)
+	return slugs, err
+}
+
+// GetRolePermissionIDs returns the permission IDs granted to a role
+func GetRolePermissionIDs(ctx context.Context, db bun.IDB, roleID int64) ([]int64, error) {
+	var ids []int64
+	err := db.NewSelect().
+		Model((*RolePermission)(nil)).
+		Column("permission_id").
+		Where("role_id = ?", roleID).
+		Scan(ctx, &ids)
+	return ids, err
+}
+
+// SyncRolePermissions replaces the permissions of a role
+func SyncRolePermissions(ctx context.Context, db bun.IDB, roleID int64, permissionIDs []int64) error {
+	if _, err := db.NewDelete().Model((*RolePermission)(nil)).Where("role_id = ?", roleID).Exec(ctx); err != nil {
+		return err
+	}
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+
+	rows := make([]RolePermission, 0, len(permissionIDs))
+	for _, id := range permissionIDs {
+		rows = append(rows, RolePermission{RoleID: roleID, PermissionID: id})
+	}
+	_, err := db.NewInsert().Model(&rows).On("CONFLICT DO NOTHING").Exec(ctx)
+	return err
+}
+
+// UserHasPermission checks a permission for the user's role
+func UserHasPermission(ctx context.Context, db bun.IDB, user *User, permission string) (bool, error) {
+	if user.Role == RoleAdmin {
+		return true, nil
+	}
+	slugs, err := GetRolePermissionSlugs(ctx, db, user.Role)
+	if err != nil {
+		return false, err
+	}
+	for _, slug := range slugs {
+		if slug == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -8,14 +8,14 @@ import (
 
 type Subcategory struct {
 	bun.BaseModel `bun:"table:subcategories"`
-	ID            int64     `bun:"id,pk,autoincrement"`
-	CategoryID    int       `bun:"category_id,notnull"`
-	Name          string    `bun:"name,notnull"`
-	Slug          string    `bun:"slug,notnull"`
-	Status        int       `bun:"status,notnull,default:1"`
-	CreatedAt     time.Time `bun:"created_at,default:now()"`
-	UpdatedAt     time.Time `bun:"updated_at,default:now()"`
+	ID            int64     `bun:"id,pk,autoincrement" json:"id"`
+	CategoryID    int       `bun:"category_id,notnull" json:"category_id"`
+	Name          string    `bun:"name,notnull" json:"name"`
+	Slug          string    `bun:"slug,notnull" json:"slug"`
+	Status        int       `bun:"status,notnull,default:1" json:"status"`
+	CreatedAt     time.Time `bun:"created_at,default:now()" json:"created_at"`
+	UpdatedAt     time.Time `bun:"updated_at,default:now()" json:"updated_at"`
 
 	// Relation with Category
-	Category *Category `bun:"rel:belongs-to,join:category_id=id"`
+	Category *Category `bun:"rel:belongs-to,join:category_id=id" json:"category,omitempty"`
 }
@@ -13,13 +13,34 @@ import (
 type User struct {
 	bun.BaseModel `bun:"table:users"`
 
-	ID        int64     `bun:"id,pk,autoincrement"`
-	Name      string    `bun:"name,notnull"`
-	Email     string    `bun:"email,unique,notnull"`
-	Password  string    `bun:"password,notnull"` // hashed password
-	Role      string    `bun:"role,notnull"`     // "admin" or "customer"
-	CreatedAt time.Time `bun:"created_at,default:current_timestamp"`
-	UpdatedAt time.Time `bun:"updated_at,default:current_timestamp,nullzero"`
+	ID        int64     `bun:"id,pk,autoincrement" json:"id"`
+	Name      string    `bun:"name,notnull" json:"name"`
+	Email     string    `bun:"email,unique,notnull" json:"email"`
+	Password  string    `bun:"password,notnull" json:"-"`    // hashed password
+	Role      string    `bun:"role,notnull" json:"role"`     // "admin" or "customer"
+	Status    int       `bun:"status,notnull" json:"status"` // 1 active, 0 disabled; no bun default so 0 can be inserted
+	CreatedAt time.Time `bun:"created_at,default:current_timestamp" json:"created_at"`
+	UpdatedAt time.Time `bun:"updated_at,default:current_timestamp,nullzero" json:"updated_at"`
+
+	// Two-factor authentication, TotpEnabledAt is zero while 2FA is off
+	TotpSecret    string    `bun:"totp_secret,nullzero" json:"-"`
+	TotpEnabledAt time.Time `bun:"totp_enabled_at,nullzero" json:"-"`
+}
+
+// User status values
+const (
+	UserStatusDisabled = 0
+	UserStatusActive   = 1
+)
+
+// IsActive reports whether the account is allowed to log in
+func (u User) IsActive() bool {
+	return u.Status == UserStatusActive
+}
+
+// TwoFactorEnabled reports whether logins need a TOTP or recovery code
+func (u User) TwoFactorEnabled() bool {
+	return !u.TotpEnabledAt.IsZero() && u.TotpSecret != ""
 }
 
 // BeforeInsert hook to set CreatedAt
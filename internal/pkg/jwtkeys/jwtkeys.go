@@ -0,0 +1,376 @@
+// Package jwtkeys manages the asymmetric keys used to sign JWTs.
+//
+// Keys live as PKCS#8 PEM files in one directory, the file name is the key ID
+// ("kid"). The newest key signs, older keys keep verifying until the grace
+// period after they were replaced has passed, so tokens issued before a
+// rotation stay valid. The directory can be shared by several instances.
+package jwtkeys
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported signing algorithms
+const (
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+const (
+	rsaKeyBits   = 2048
+	kidTimeFmt   = "20060102T150405.000Z"
+	keyExtension = ".pem"
+
+	// Unknown kids trigger a reload at most this often
+	missReloadInterval = 10 * time.Second
+)
+
+var ErrNoSigningKey = errors.New("jwtkeys: no signing key")
+
+// Algorithms returns the algorithms tokens may be signed with
+func Algorithms() []string {
+	return []string{RS256, EdDSA}
+}
+
+// Key is one signing key
+type Key struct {
+	ID        string
+	Algorithm string
+	CreatedAt time.Time
+	Private   crypto.Signer
+}
+
+// Public returns the verification key
+func (k *Key) Public() crypto.PublicKey {
+	return k.Private.Public()
+}
+
+// SigningMethod returns the jwt method matching the key type
+func (k *Key) SigningMethod() jwt.SigningMethod {
+	if k.Algorithm == EdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// Options configure a Manager
+type Options struct {
+	Dir              string
+	Algorithm        string        // algorithm of newly generated keys
+	RotationInterval time.Duration // age after which a new signing key is generated, 0 = never
+	GracePeriod      time.Duration // how long a replaced key keeps verifying tokens
+}
+
+// Manager holds the keys of a directory
+type Manager struct {
+	opts Options
+
+	mu         sync.RWMutex
+	keys       []*Key // oldest first
+	reloadedAt time.Time
+}
+
+// New loads the keys of opts.Dir and generates a signing key when none fits the options
+func New(opts Options) (*Manager, error) {
+	if opts.Algorithm != RS256 && opts.Algorithm != EdDSA {
+		return nil, fmt.Errorf("jwtkeys: unsupported algorithm %q (use %s or %s)", opts.Algorithm, RS256, EdDSA)
+	}
+	if opts.Dir == "" {
+		return nil, errors.New("jwtkeys: keys directory is not set")
+	}
+	if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	m := &Manager{opts: opts}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	if _, err := m.RotateIfDue(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload reads the key files again, e.g. after another instance rotated
+func (m *Manager) Reload() error {
+	entries, err := os.ReadDir(m.opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	var keys []*Key
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), keyExtension) {
+			continue
+		}
+		key, err := readKey(filepath.Join(m.opts.Dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+
+	m.mu.Lock()
+	m.keys = keys
+	m.reloadedAt = time.Now()
+	m.mu.Unlock()
+	return nil
+}
+
+// Current returns the key new tokens are signed with
+func (m *Manager) Current() (*Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.keys) == 0 {
+		return nil, ErrNoSigningKey
+	}
+	return m.keys[len(m.keys)-1], nil
+}
+
+// Lookup returns a key that may still verify tokens
+func (m *Manager) Lookup(kid string) (*Key, bool) {
+	for _, key := range m.VerificationKeys() {
+		if key.ID == kid {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// VerificationKeys returns the signing key and the replaced keys still within their grace period
+func (m *Manager) VerificationKeys() []*Key {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]*Key, 0, len(m.keys))
+	for i, key := range m.keys {
+		if i < len(m.keys)-1 && now.After(m.keys[i+1].CreatedAt.Add(m.opts.GracePeriod)) {
+			continue // replaced longer than the grace period ago
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Rotate generates a new signing key; the previous one keeps verifying for the grace period
+func (m *Manager) Rotate() (*Key, error) {
+	key, err := generateKey(m.opts.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeKey(m.opts.Dir, key); err != nil {
+		return nil, err
+	}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// RotateIfDue rotates when there is no key yet, the algorithm changed or the
+// signing key is older than the rotation interval. Expired keys are removed.
+func (m *Manager) RotateIfDue() (bool, error) {
+	current, err := m.Current()
+	due := err != nil ||
+		current.Algorithm != m.opts.Algorithm ||
+		(m.opts.RotationInterval > 0 && time.Since(current.CreatedAt) >= m.opts.RotationInterval)
+
+	if due {
+		if _, err := m.Rotate(); err != nil {
+			return false, err
+		}
+	}
+	_, err = m.Prune()
+	return due, err
+}
+
+// Prune deletes the files of keys past their grace period
+func (m *Manager) Prune() (int, error) {
+	m.mu.RLock()
+	var expired []*Key
+	now := time.Now()
+	for i := 0; i < len(m.keys)-1; i++ {
+		if now.After(m.keys[i+1].CreatedAt.Add(m.opts.GracePeriod)) {
+			expired = append(expired, m.keys[i])
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, key := range expired {
+		if err := os.Remove(filepath.Join(m.opts.Dir, key.ID+keyExtension)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return 0, err
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+	return len(expired), m.Reload()
+}
+
+// Run reloads the directory and rotates on schedule until ctx is done
+func (m *Manager) Run(ctx context.Context, every time.Duration, onError func(error)) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := m.Reload()
+			if err == nil {
+				_, err = m.RotateIfDue()
+			}
+			if err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Keyfunc verifies the token header against the key it names: the kid must be
+// known and the alg must be the algorithm of that key
+func (m *Manager) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("jwtkeys: token has no kid")
+	}
+
+	key, ok := m.Lookup(kid)
+	if !ok && m.stale() {
+		// Possibly rotated by another instance a moment ago
+		if err := m.Reload(); err == nil {
+			key, ok = m.Lookup(kid)
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("jwtkeys: unknown kid %q", kid)
+	}
+
+	if t.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("jwtkeys: unexpected algorithm %q for kid %q", t.Method.Alg(), kid)
+	}
+	return key.Public(), nil
+}
+
+func (m *Manager) stale() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return time.Since(m.reloadedAt) > missReloadInterval
+}
+
+func generateKey(alg string) (*Key, error) {
+	var signer crypto.Signer
+	switch alg {
+	case RS256:
+		k, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		signer = k
+	case EdDSA:
+		_, k, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		signer = k
+	default:
+		return nil, fmt.Errorf("jwtkeys: unsupported algorithm %q", alg)
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	// The kid carries the creation time (millisecond precision keeps the order of quick rotations)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return &Key{
+		ID:        now.Format(kidTimeFmt) + "-" + hex.EncodeToString(suffix),
+		Algorithm: alg,
+		CreatedAt: now,
+		Private:   signer,
+	}, nil
+}
+
+func writeKey(dir string, key *Key) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.Private)
+	if err != nil {
+		return err
+	}
+
+	// Write + rename so other instances never read half a file
+	tmp, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := pem.Encode(tmp, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, key.ID+keyExtension))
+}
+
+func readKey(path string) (*Key, error) {
+	kid := strings.TrimSuffix(filepath.Base(path), keyExtension)
+
+	prefix, _, _ := strings.Cut(kid, "-")
+	created, err := time.Parse(kidTimeFmt, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: %s: kid does not start with a creation time", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("jwtkeys: %s: no PEM data", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("jwtkeys: %s: %w", path, err)
+	}
+
+	key := &Key{ID: kid, CreatedAt: created}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Private = RS256, k
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Private = EdDSA, k
+	default:
+		return nil, fmt.Errorf("jwtkeys: %s: unsupported key type %T", path, parsed)
+	}
+	return key, nil
+}
@@ -0,0 +1,87 @@
+// Package mailer sends application emails through a pluggable Sender.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"gin-app/config"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers a Message. Implement it to plug in SMTP, SES etc.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogSender writes mails to a file (or the standard logger when Path is empty).
+// Useful for local testing where no mail server is available.
+type LogSender struct {
+	From string
+	Path string
+
+	mu sync.Mutex
+}
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "----- %s -----\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&b, "From: %s\nTo: %s\nSubject: %s\n\n%s\n\n", s.From, msg.To, msg.Subject, msg.Body)
+
+	if s.Path == "" {
+		slog.InfoContext(ctx, "Mail (log driver)", "to", msg.To, "subject", msg.Subject, "mail", b.String())
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(b.String())
+	return err
+}
+
+var (
+	defaultSender Sender
+	once          sync.Once
+)
+
+// Default returns the Sender configured by the mail section of config.yaml
+func Default() Sender {
+	once.Do(func() {
+		conf := config.AppConfig.Mail
+		switch conf.Driver {
+		case "", "log":
+			defaultSender = &LogSender{From: conf.From, Path: conf.LogPath}
+		default:
+			slog.Warn("Unknown mail driver, falling back to log", "driver", conf.Driver)
+			defaultSender = &LogSender{From: conf.From, Path: conf.LogPath}
+		}
+	})
+	return defaultSender
+}
+
+// SetDefault replaces the default Sender (e.g. with an SMTP implementation)
+func SetDefault(s Sender) {
+	once.Do(func() {})
+	defaultSender = s
+}
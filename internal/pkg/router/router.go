@@ -1,18 +1,25 @@
 package router
 
 import (
+	"gin-app/config"
+	"gin-app/internal/app/http/middleware"
 	"gin-app/internal/routes"
 	"gin-app/internal/utils"
 	"html/template"
-	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func SetupRouter() *gin.Engine {
 
-	r := gin.Default()
+	r := gin.New()
+	r.ContextWithFallback = true // bun/redis calls given the *gin.Context see the request ID
+	if config.AppConfig.Tracing.Enabled {
+		r.Use(otelgin.Middleware(config.TracingServiceName())) // span per request, parent of the SQL/Redis spans
+	}
+	r.Use(middleware.RequestID(), middleware.Metrics(), middleware.RequestLogger(), middleware.Recovery(), middleware.ErrorHandler())
 
 	r.SetFuncMap(template.FuncMap{
 		"asset": utils.Asset, // utils.Asset কে "asset" নামে template এ expose করলাম
@@ -20,6 +27,7 @@ func SetupRouter() *gin.Engine {
 		"formatDate": func(t time.Time) string {
 			return t.Format("02 Jan 2006")
 		},
+		"csrfField": utils.CsrfFieldHTML, // {{csrfField .csrf_token}} inside admin forms
 	})
 
 	// load html
@@ -32,9 +40,7 @@ func SetupRouter() *gin.Engine {
 	})
 	// 404 page
 	r.NoRoute(func(c *gin.Context) {
-		c.HTML(http.StatusNotFound, "404.html", gin.H{
-			"title": "Page Not Found",
-		})
+		utils.Fail(c, utils.NotFound("Page not found")) // JSON for the API
 	})
 
 	// ✅ Register API routes
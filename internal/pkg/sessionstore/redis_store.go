@@ -0,0 +1,132 @@
+// Package sessionstore is a gin-contrib/sessions store that keeps session
+// values in Redis. The browser only holds a random session ID.
+package sessionstore
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	gsessions "github.com/gorilla/sessions"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	idBytes = 32
+
+	// Redis expiry of sessions whose cookie has no Max-Age (browser session)
+	defaultTTL = 24 * time.Hour
+)
+
+// RedisStore stores gob encoded session values under prefix+ID
+type RedisStore struct {
+	client  redis.UniversalClient
+	prefix  string
+	options *gsessions.Options
+}
+
+var _ sessions.Store = (*RedisStore)(nil)
+
+// NewRedisStore creates a store; options.MaxAge is also the lifetime of the
+// Redis key. Values of custom types must be registered with gob.Register.
+func NewRedisStore(client redis.UniversalClient, prefix string, options sessions.Options) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix, options: options.ToGorillaOptions()}
+}
+
+// Options replaces the cookie options of new sessions
+func (s *RedisStore) Options(options sessions.Options) {
+	s.options = options.ToGorillaOptions()
+}
+
+// Get returns the session of the request, loading it once per request
+func (s *RedisStore) Get(r *http.Request, name string) (*gsessions.Session, error) {
+	return gsessions.GetRegistry(r).Get(s, name)
+}
+
+// New loads the session named by the cookie, or starts an empty one when the
+// cookie is missing, malformed or its session expired
+func (s *RedisStore) New(r *http.Request, name string) (*gsessions.Session, error) {
+	session := gsessions.NewSession(s, name)
+	options := *s.options
+	session.Options = &options
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil || !validID(cookie.Value) {
+		return session, nil
+	}
+
+	data, err := s.client.Get(r.Context(), s.prefix+cookie.Value).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return session, nil
+	}
+	if err != nil {
+		return session, err
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&session.Values); err != nil {
+		return session, err
+	}
+
+	session.ID = cookie.Value
+	session.IsNew = false
+	return session, nil
+}
+
+// Save writes the values to Redis and (re)sets the cookie; a negative MaxAge
+// deletes the session
+func (s *RedisStore) Save(r *http.Request, w http.ResponseWriter, session *gsessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			if err := s.client.Del(r.Context(), s.prefix+session.ID).Err(); err != nil {
+				return err
+			}
+		}
+		http.SetCookie(w, gsessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	if session.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return err
+		}
+		session.ID = id
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(session.Values); err != nil {
+		return err
+	}
+
+	ttl := time.Duration(session.Options.MaxAge) * time.Second
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	if err := s.client.Set(r.Context(), s.prefix+session.ID, buf.Bytes(), ttl).Err(); err != nil {
+		return err
+	}
+
+	http.SetCookie(w, gsessions.NewCookie(session.Name(), session.ID, session.Options))
+	return nil
+}
+
+func newID() (string, error) {
+	b := make([]byte, idBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func validID(id string) bool {
+	if len(id) != idBytes*2 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
@@ -0,0 +1,114 @@
+// Package tracing sets up the OpenTelemetry tracer provider: the exporter
+// (OTLP over HTTP, stdout or a file), sampling and the service resource.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporters
+const (
+	ExporterOTLP   = "otlp"   // OTLP/HTTP collector (Jaeger, Tempo, ...)
+	ExporterStdout = "stdout" // pretty printed spans, for local use
+	ExporterFile   = "file"   // one JSON span per line appended to File
+)
+
+type Options struct {
+	ServiceName string
+	Environment string
+	Exporter    string
+	Endpoint    string  // host:port of the OTLP/HTTP receiver; empty = $OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+	Insecure    bool    // OTLP over plain HTTP
+	File        string  // file exporter
+	SampleRate  float64 // share of new traces recorded, 0..1; child spans follow their parent
+}
+
+// Setup installs the global tracer provider and W3C trace context propagation.
+// The returned function flushes pending spans and must be called on shutdown.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	if opts.SampleRate < 0 || opts.SampleRate > 1 {
+		return nil, fmt.Errorf("sample rate must be between 0 and 1, got %v", opts.SampleRate)
+	}
+
+	exporter, closeOutput, err := newExporter(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithAttributes(
+			attribute.String("service.name", opts.ServiceName),
+			attribute.String("deployment.environment.name", opts.Environment),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRate))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		return errors.Join(provider.Shutdown(ctx), closeOutput())
+	}, nil
+}
+
+func newExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, func() error, error) {
+	noop := func() error { return nil }
+
+	switch opts.Exporter {
+	case ExporterOTLP:
+		var options []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, options...)
+		return exporter, noop, err
+
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		return exporter, noop, err
+
+	case ExporterFile:
+		if opts.File == "" {
+			return nil, nil, errors.New("the file exporter needs a file")
+		}
+		if err := os.MkdirAll(filepath.Dir(opts.File), os.ModePerm); err != nil {
+			return nil, nil, err
+		}
+		f, err := os.OpenFile(opts.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		return exporter, f.Close, nil
+	}
+	return nil, nil, fmt.Errorf("unknown exporter %q, use otlp, stdout or file", opts.Exporter)
+}
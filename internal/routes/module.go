@@ -1,6 +1,10 @@
 package routes
 
 import (
+	"gin-app/config"
+	api_controller "gin-app/internal/app/http/controllers/api"
+	"gin-app/internal/app/http/middleware"
+	"gin-app/internal/pkg/metrics"
 	v1 "gin-app/internal/routes/v1"
 
 	"github.com/gin-gonic/gin"
@@ -9,4 +13,16 @@ import (
 func RegisterRoutes(router *gin.Engine) {
 	v1.RegisterAdminRoutes(router.Group("/admin"))
 	v1.RegisterApiRoutes(router.Group("/api/v1"))
+
+	// Public signing keys for other services
+	router.GET("/.well-known/jwks.json", api_controller.Jwks)
+
+	// Liveness and readiness probes
+	router.GET("/healthz", api_controller.Healthz)
+	router.GET("/readyz", api_controller.Readyz)
+
+	// Prometheus metrics, unless served on their own port (metrics.addr)
+	if conf := config.AppConfig.Metrics; conf.Enabled && conf.Addr == "" {
+		router.GET("/metrics", middleware.MetricsToken(), gin.WrapH(metrics.Handler()))
+	}
 }
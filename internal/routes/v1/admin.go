@@ -8,48 +8,92 @@ import (
 )
 
 func RegisterAdminRoutes(rg *gin.RouterGroup) {
+	// Every admin form and AJAX call must carry the CSRF token
+	rg.Use(middleware.SessionMiddleware(), middleware.CsrfMiddleware())
 
 	// Admin routes go here
 	auth := rg.Group("/").Use(middleware.AdminGuestMiddleware())
 	{
 		auth.GET("/login", admin_controller.AdminLogin)
 		auth.POST("/login", admin_controller.AdminLoginAction)
+		auth.GET("/login/2fa", admin_controller.AdminTwoFactorChallenge)
+		auth.POST("/login/2fa", admin_controller.AdminTwoFactorChallengeAction)
 		auth.GET("/forget-password", admin_controller.AdminForgetPassword)
-		auth.GET("/admin-create", admin_controller.AdminCreate)
+		auth.POST("/forget-password", admin_controller.AdminForgetPasswordAction)
+		auth.GET("/reset-password/:token", admin_controller.AdminResetPassword)
+		auth.POST("/reset-password", admin_controller.AdminResetPasswordAction)
 	}
 
 	// After login
 	admin := rg.Group("/").Use(middleware.AdminAuthMiddleware())
 	{
 		admin.GET("/dashboard", admin_controller.AdminDashboard)
-		admin.GET("/logout", admin_controller.AdminLogout)
+		admin.POST("/logout", admin_controller.AdminLogout)
+
+		// Two-factor authentication of the logged in admin
+		admin.GET("/two-factor", admin_controller.AdminTwoFactor)
+		admin.POST("/two-factor/enable", admin_controller.AdminTwoFactorEnable)
+		admin.POST("/two-factor/disable", admin_controller.AdminTwoFactorDisable)
+		admin.POST("/two-factor/recovery-codes", admin_controller.AdminTwoFactorRecoveryCodes)
+
+		// Sessions (devices) of the logged in admin
+		admin.GET("/sessions", admin_controller.AdminSessionList)
+		admin.DELETE("/sessions/:scope/:id", admin_controller.AdminRevokeSession)
 
 		// Job Type Routes
-		admin.GET("/job-type-list", admin_controller.AdminJobTypeList)
-		admin.GET("/job-type-create", admin_controller.AdminJobTypeCreate)
-		admin.POST("/job-type-store", admin_controller.AdminJobTypeStore)
-		admin.POST("/job-type-status/:id", admin_controller.AdminToggleJobTypeStatus)
-		admin.DELETE("/job-type-delete/:id", admin_controller.AdminDeleteJobType)
-		admin.GET("/job-type-edit/:id", admin_controller.AdminEditJobType)
-		admin.POST("/job-type-update/:id", admin_controller.AdminUpdateJobType)
+		admin.GET("/job-type-list", middleware.RequirePermission("job_type.view"), admin_controller.AdminJobTypeList)
+		admin.GET("/job-type-create", middleware.RequirePermission("job_type.create"), admin_controller.AdminJobTypeCreate)
+		admin.POST("/job-type-store", middleware.RequirePermission("job_type.create"), admin_controller.AdminJobTypeStore)
+		admin.POST("/job-type-status/:id", middleware.RequirePermission("job_type.update"), admin_controller.AdminToggleJobTypeStatus)
+		admin.DELETE("/job-type-delete/:id", middleware.RequirePermission("job_type.delete"), admin_controller.AdminDeleteJobType)
+		admin.GET("/job-type-edit/:id", middleware.RequirePermission("job_type.update"), admin_controller.AdminEditJobType)
+		admin.POST("/job-type-update/:id", middleware.RequirePermission("job_type.update"), admin_controller.AdminUpdateJobType)
 
 		// Category Routes
-		admin.GET("/category-list", admin_controller.AdminCategoryList)
-		admin.GET("/category-create", admin_controller.AdminCategoryCreate)
-		admin.POST("/category-store", admin_controller.AdminCategoryStore)
-		admin.GET("/category-edit/:id", admin_controller.AdminEditCategory)
-		admin.POST("/category-update/:id", admin_controller.AdminUpdateCategory)
-		admin.DELETE("/category-delete/:id", admin_controller.AdminDeleteCategory)
-		admin.POST("/category-status/:id", admin_controller.AdminToggleCategoryStatus)
+		admin.GET("/category-list", middleware.RequirePermission("category.view"), admin_controller.AdminCategoryList)
+		admin.GET("/category-create", middleware.RequirePermission("category.create"), admin_controller.AdminCategoryCreate)
+		admin.POST("/category-store", middleware.RequirePermission("category.create"), admin_controller.AdminCategoryStore)
+		admin.GET("/category-edit/:id", middleware.RequirePermission("category.update"), admin_controller.AdminEditCategory)
+		admin.POST("/category-update/:id", middleware.RequirePermission("category.update"), admin_controller.AdminUpdateCategory)
+		admin.DELETE("/category-delete/:id", middleware.RequirePermission("category.delete"), admin_controller.AdminDeleteCategory)
+		admin.POST("/category-status/:id", middleware.RequirePermission("category.update"), admin_controller.AdminToggleCategoryStatus)
 
 		// Sub category routes
-		admin.GET("/subcategory-list", admin_controller.AdminSubCategoryList)
-		admin.GET("/subcategory-create", admin_controller.AdminSubCategoryCreate)
-		admin.POST("/subcategory-store", admin_controller.AdminSubCategoryStore)
-		admin.GET("/subcategory-edit/:id", admin_controller.AdminEditSubCategory)
-		admin.POST("/subcategory-update/:id", admin_controller.AdminUpdateSubCategory)
-		admin.DELETE("/subcategory-delete/:id", admin_controller.AdminDeleteSubCategory)
-		admin.POST("/subcategory-status/:id", admin_controller.AdminToggleSubCategoryStatus)
+		admin.GET("/subcategory-list", middleware.RequirePermission("subcategory.view"), admin_controller.AdminSubCategoryList)
+		admin.GET("/subcategory-create", middleware.RequirePermission("subcategory.create"), admin_controller.AdminSubCategoryCreate)
+		admin.POST("/subcategory-store", middleware.RequirePermission("subcategory.create"), admin_controller.AdminSubCategoryStore)
+		admin.GET("/subcategory-edit/:id", middleware.RequirePermission("subcategory.update"), admin_controller.AdminEditSubCategory)
+		admin.POST("/subcategory-update/:id", middleware.RequirePermission("subcategory.update"), admin_controller.AdminUpdateSubCategory)
+		admin.DELETE("/subcategory-delete/:id", middleware.RequirePermission("subcategory.delete"), admin_controller.AdminDeleteSubCategory)
+		admin.POST("/subcategory-status/:id", middleware.RequirePermission("subcategory.update"), admin_controller.AdminToggleSubCategoryStatus)
+	}
+
+	// User management routes
+	users := rg.Group("/").Use(middleware.AdminAuthMiddleware())
+	{
+		users.GET("/user-list", middleware.RequirePermission("user.view"), admin_controller.AdminUserList)
+		users.GET("/user-create", middleware.RequirePermission("user.create"), admin_controller.AdminUserCreate)
+		users.POST("/user-store", middleware.RequirePermission("user.create"), admin_controller.AdminUserStore)
+		users.GET("/user-edit/:id", middleware.RequirePermission("user.update"), admin_controller.AdminEditUser)
+		users.POST("/user-update/:id", middleware.RequirePermission("user.update"), admin_controller.AdminUpdateUser)
+		users.POST("/user-password/:id", middleware.RequirePermission("user.update"), admin_controller.AdminUpdateUserPassword)
+		users.POST("/user-status/:id", middleware.RequirePermission("user.update"), admin_controller.AdminToggleUserStatus)
+		users.DELETE("/user-delete/:id", middleware.RequirePermission("user.delete"), admin_controller.AdminDeleteUser)
+		users.GET("/login-locks", middleware.RequirePermission("user.update"), admin_controller.AdminLoginLockList)
+		users.POST("/login-unlock", middleware.RequirePermission("user.update"), admin_controller.AdminUnlockLogin)
+	}
+
+	// Role & permission routes
+	roles := rg.Group("/").Use(middleware.AdminAuthMiddleware(), middleware.RequirePermission("role.manage"))
+	{
+		roles.GET("/role-list", admin_controller.AdminRoleList)
+		roles.GET("/role-create", admin_controller.AdminRoleCreate)
+		roles.POST("/role-store", admin_controller.AdminRoleStore)
+		roles.GET("/role-edit/:id", admin_controller.AdminEditRole)
+		roles.POST("/role-update/:id", admin_controller.AdminUpdateRole)
+		roles.DELETE("/role-delete/:id", admin_controller.AdminDeleteRole)
+		roles.GET("/user-role-list", admin_controller.AdminUserRoleList)
+		roles.POST("/user-role/:id", admin_controller.AdminAssignUserRole)
 	}
 
 	// Refresh token
@@ -1,6 +1,9 @@
 package v1
 
 import (
+	api_controller "gin-app/internal/app/http/controllers/api"
+	"gin-app/internal/app/http/middleware"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -9,4 +12,38 @@ func RegisterApiRoutes(rg *gin.RouterGroup) {
 	rg.GET("/", func(c *gin.Context) {
 		c.String(200, "Welcome to the API")
 	})
+
+	// Auth routes
+	rg.POST("/auth/login", api_controller.AuthLogin)
+	rg.POST("/auth/refresh", api_controller.AuthRefresh)
+
+	// Bearer token required
+	api := rg.Group("/").Use(middleware.ApiAuthMiddleware())
+
+	api.POST("/auth/logout", api_controller.AuthLogout)
+	api.GET("/auth/me", api_controller.AuthMe)
+
+	// Category routes
+	api.GET("/categories", api_controller.CategoryIndex)
+	api.POST("/categories", middleware.RequirePermission("category.create"), api_controller.CategoryStore)
+	api.GET("/categories/:id", api_controller.CategoryShow)
+	api.PUT("/categories/:id", middleware.RequirePermission("category.update"), api_controller.CategoryUpdate)
+	api.DELETE("/categories/:id", middleware.RequirePermission("category.delete"), api_controller.CategoryDelete)
+	api.PATCH("/categories/:id/status", middleware.RequirePermission("category.update"), api_controller.CategoryToggleStatus)
+
+	// Subcategory routes
+	api.GET("/subcategories", api_controller.SubcategoryIndex)
+	api.POST("/subcategories", middleware.RequirePermission("subcategory.create"), api_controller.SubcategoryStore)
+	api.GET("/subcategories/:id", api_controller.SubcategoryShow)
+	api.PUT("/subcategories/:id", middleware.RequirePermission("subcategory.update"), api_controller.SubcategoryUpdate)
+	api.DELETE("/subcategories/:id", middleware.RequirePermission("subcategory.delete"), api_controller.SubcategoryDelete)
+	api.PATCH("/subcategories/:id/status", middleware.RequirePermission("subcategory.update"), api_controller.SubcategoryToggleStatus)
+
+	// Job type routes
+	api.GET("/job-types", api_controller.JobTypeIndex)
+	api.POST("/job-types", middleware.RequirePermission("job_type.create"), api_controller.JobTypeStore)
+	api.GET("/job-types/:id", api_controller.JobTypeShow)
+	api.PUT("/job-types/:id", middleware.RequirePermission("job_type.update"), api_controller.JobTypeUpdate)
+	api.DELETE("/job-types/:id", middleware.RequirePermission("job_type.delete"), api_controller.JobTypeDelete)
+	api.PATCH("/job-types/:id/status", middleware.RequirePermission("job_type.update"), api_controller.JobTypeToggleStatus)
 }
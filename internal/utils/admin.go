@@ -1,45 +1,73 @@
 package utils
 
 import (
-	"context"
 	"errors"
 	"gin-app/config"
 	"gin-app/internal/models"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 )
 
+// AdminContextKey is where AdminAuthMiddleware keeps the authenticated *models.User
+const AdminContextKey = "admin"
+
+// SetLoggedInAdmin stores the authenticated admin in the gin context
+func SetLoggedInAdmin(c *gin.Context, admin *models.User) {
+	c.Set(AdminContextKey, admin)
+}
+
+// CurrentAdmin returns the admin set by AdminAuthMiddleware, or nil
+func CurrentAdmin(c *gin.Context) *models.User {
+	if v, ok := c.Get(AdminContextKey); ok {
+		if admin, ok := v.(*models.User); ok {
+			return admin
+		}
+	}
+	return nil
+}
+
+// ApiUserContextKey is where ApiAuthMiddleware keeps the authenticated *models.User
+const ApiUserContextKey = "api_user"
+
+// ApiTokenContextKey is where ApiAuthMiddleware keeps the Bearer access token
+const ApiTokenContextKey = "api_token"
+
+// CurrentApiUser returns the user set by ApiAuthMiddleware, or nil
+func CurrentApiUser(c *gin.Context) *models.User {
+	if v, ok := c.Get(ApiUserContextKey); ok {
+		if user, ok := v.(*models.User); ok {
+			return user
+		}
+	}
+	return nil
+}
+
 // AUthenticate admin data
 func GetLoggedInAdmin(c *gin.Context) (*models.User, int64, error) {
+	// Already loaded by AdminAuthMiddleware
+	if admin := CurrentAdmin(c); admin != nil {
+		return admin, admin.ID, nil
+	}
+
 	accessToken, err := c.Cookie("admin_access")
 	if err != nil || accessToken == "" {
 		return nil, 0, errors.New("missing access token")
 	}
 
-	token, err := jwt.Parse(accessToken, func(t *jwt.Token) (interface{}, error) {
-		return []byte(config.AppConfig.App.JwtSecret), nil
-	})
-	if err != nil || !token.Valid {
+	// Signature, expiry, issuer, admin audience and access type
+	claims, err := ParseToken(accessToken, AdminScope, AccessTokenType)
+	if err != nil {
 		return nil, 0, errors.New("invalid access token")
 	}
-
-	// Use jwt.MapClaims
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, 0, errors.New("cannot parse claims")
-	}
-
-	userIDFloat, ok := claims["user_id"].(float64)
-	if !ok {
-		return nil, 0, errors.New("user_id not found in token claims")
+	adminID, err := claims.UserID()
+	if err != nil {
+		return nil, 0, err
 	}
-	adminID := int64(userIDFloat)
 
 	var admin models.User
 	err = config.DB.NewSelect().Model(&admin).
 		// Column("id", "name", "email").  // if i need specific fields
-		Where("id = ?", adminID).Scan(context.Background())
+		Where("id = ?", adminID).Scan(c.Request.Context())
 	if err != nil {
 		return nil, adminID, err
 	}
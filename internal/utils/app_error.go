@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Shown for internal errors instead of their cause
+const genericErrorMessage = "Something went wrong, please try again later"
+
+// ErrorKind classifies an AppError; it decides the status code
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindValidation
+	KindConflict
+	KindUnauthorized
+	KindForbidden
+)
+
+// AppError is an error with a message that is safe to show to users. The
+// cause (a DB error, ...) only reaches the logs.
+type AppError struct {
+	Kind    ErrorKind
+	Message string
+	Fields  map[string]string // validation errors per field
+	Err     error             // internal cause, never rendered
+}
+
+func (e *AppError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Status is the HTTP status code of the error kind
+func (e *AppError) Status() int {
+	switch e.Kind {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindValidation:
+		return http.StatusUnprocessableEntity
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+func NotFound(message string) *AppError {
+	return &AppError{Kind: KindNotFound, Message: message}
+}
+
+func Validation(message string, fields map[string]string) *AppError {
+	return &AppError{Kind: KindValidation, Message: message, Fields: fields}
+}
+
+func Conflict(message string) *AppError {
+	return &AppError{Kind: KindConflict, Message: message}
+}
+
+func Unauthorized(message string) *AppError {
+	return &AppError{Kind: KindUnauthorized, Message: message}
+}
+
+func Forbidden(message string) *AppError {
+	return &AppError{Kind: KindForbidden, Message: message}
+}
+
+// Internal wraps an unexpected error; users only see message
+func Internal(err error, message string) *AppError {
+	return &AppError{Kind: KindInternal, Message: message, Err: err}
+}
+
+// AsAppError returns err as an AppError; anything else becomes an internal error
+func AsAppError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err, genericErrorMessage)
+}
+
+// Fail hands err to the error middleware, which renders it as HTML or JSON,
+// and stops the chain. Handlers return right after calling it.
+func Fail(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// NotFoundOrInternal maps the error of a single-row lookup: no rows becomes
+// NotFound(message), anything else an internal error
+func NotFoundOrInternal(err error, message string) *AppError {
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound(message)
+	}
+	return Internal(err, genericErrorMessage)
+}
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -37,6 +39,15 @@ func MakeSlug(s string) string {
 	return s
 }
 
+// RandomToken returns a cryptographically secure random hex string of n bytes
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
 func Asset(path string) string {
 	return "/static/" + path
 }
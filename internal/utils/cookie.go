@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"gin-app/config"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetCookie sets an HttpOnly cookie on "/" with the Secure/SameSite/Domain
+// attributes of the config. maxAge < 0 deletes the cookie, 0 keeps it for the
+// browser session.
+func SetCookie(c *gin.Context, name, value string, maxAge int) {
+	conf := config.AppConfig.Cookie
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   conf.Domain,
+		MaxAge:   maxAge,
+		Secure:   conf.Secure,
+		HttpOnly: true,
+		SameSite: CookieSameSite(conf.SameSite),
+	})
+}
+
+// ClearCookie deletes a cookie set with SetCookie
+func ClearCookie(c *gin.Context, name string) {
+	SetCookie(c, name, "", -1)
+}
+
+// CookieSameSite converts the cookie.same_site setting
+func CookieSameSite(mode string) http.SameSite {
+	switch strings.ToLower(mode) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
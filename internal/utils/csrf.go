@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"crypto/subtle"
+	"gin-app/config"
+	"html/template"
+	"net/http"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	gsessions "github.com/gorilla/sessions"
+)
+
+// CSRF protection of the admin panel
+//
+// Every browser session gets a random token kept in the Redis backed session
+// (see flash.go), so only the server knows it. Pages embed the token in forms
+// (_csrf field) and in a meta tag that the layout script sends as
+// X-CSRF-Token on fetch/jQuery requests. Unsafe requests must echo it back,
+// which a cross-site page can't do because it never sees the token.
+const (
+	CsrfField  = "_csrf"
+	CsrfHeader = "X-CSRF-Token"
+
+	csrfSessionKey = "csrf_token"
+	csrfContextKey = "csrf_token"
+	csrfTokenBytes = 32
+)
+
+// EnsureCsrfToken returns the token of the session, issuing one when missing
+func EnsureCsrfToken(c *gin.Context) (string, error) {
+	if token := c.GetString(csrfContextKey); token != "" {
+		return token, nil
+	}
+
+	token, _ := sessions.Default(c).Get(csrfSessionKey).(string)
+	if len(token) != csrfTokenBytes*2 {
+		return RotateCsrfToken(c)
+	}
+	c.Set(csrfContextKey, token)
+	return token, nil
+}
+
+// RotateCsrfToken issues a new token, e.g. when the user logs in. The session
+// also moves to a new ID, so a session cookie planted before (for example from
+// a sibling subdomain) doesn't know the token.
+func RotateCsrfToken(c *gin.Context) (string, error) {
+	token, err := RandomToken(csrfTokenBytes)
+	if err != nil {
+		return "", err
+	}
+	if err := renewSessionID(c); err != nil {
+		return "", err
+	}
+	session := sessions.Default(c)
+	session.Set(csrfSessionKey, token)
+	if err := session.Save(); err != nil {
+		return "", err
+	}
+	c.Set(csrfContextKey, token)
+	return token, nil
+}
+
+// renewSessionID drops the Redis key of the current session; the next Save
+// stores the values under a new ID and sets a new cookie
+func renewSessionID(c *gin.Context) error {
+	gs, ok := sessions.Default(c).(interface{ Session() *gsessions.Session })
+	if !ok {
+		return nil
+	}
+	session := gs.Session()
+	if session.ID == "" {
+		return nil
+	}
+	if err := config.RedisClient.Del(c.Request.Context(), SessionPrefix+session.ID).Err(); err != nil {
+		return err
+	}
+	session.ID = ""
+	return nil
+}
+
+// CsrfToken returns the token of the current request (set by CsrfMiddleware)
+func CsrfToken(c *gin.Context) string {
+	return c.GetString(csrfContextKey)
+}
+
+// ValidCsrfToken checks the X-CSRF-Token header or the _csrf form field against the session
+func ValidCsrfToken(c *gin.Context) bool {
+	expected, _ := sessions.Default(c).Get(csrfSessionKey).(string)
+	if expected == "" {
+		return false
+	}
+
+	sent := c.GetHeader(CsrfHeader)
+	if sent == "" {
+		sent = c.PostForm(CsrfField)
+	}
+	return subtle.ConstantTimeCompare([]byte(sent), []byte(expected)) == 1
+}
+
+// CsrfSafeMethod reports whether the method can't change state
+func CsrfSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// CsrfFieldHTML renders the hidden form input, used in templates as {{csrfField .csrf_token}}
+func CsrfFieldHTML(token string) template.HTML {
+	return template.HTML(`<input type="hidden" name="` + CsrfField + `" value="` + template.HTMLEscapeString(token) + `">`)
+}
+
+// HTML renders an admin template with the values every layout needs (the CSRF
+// token and the flash messages of the previous request)
+func HTML(c *gin.Context, status int, name string, data gin.H) {
+	if data == nil {
+		data = gin.H{}
+	}
+	if _, ok := data["csrf_token"]; !ok {
+		data["csrf_token"] = CsrfToken(c)
+	}
+	addFlashData(c, data)
+	c.HTML(status, name, data)
+}
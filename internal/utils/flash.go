@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"encoding/gob"
+	"gin-app/internal/pkg/logger"
+	"log/slog"
+	"net/url"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// Flash messages of the admin panel
+//
+// A handler that redirects stores the message (after a failed validation also
+// the field errors and the submitted input) in the Redis backed session; the
+// next page rendered with HTML shows it once. Nothing travels in the URL, so
+// it can't be spoofed and is gone on refresh.
+const (
+	SessionCookie = "admin_sid"
+	SessionPrefix = "admin_web_session:"
+
+	flashSessionKey = "flash"
+	flashContextKey = "flash"
+)
+
+// Flash is what a redirect hands to the next page
+type Flash struct {
+	Success string
+	Error   string
+	Warning string
+	Errors  map[string]string // validation errors per field
+	Old     url.Values        // submitted form, without passwords and tokens
+}
+
+func init() {
+	gob.Register(Flash{})
+}
+
+func FlashSuccess(c *gin.Context, message string) {
+	addFlash(c, func(f *Flash) { f.Success = message })
+}
+
+func FlashError(c *gin.Context, message string) {
+	addFlash(c, func(f *Flash) { f.Error = message })
+}
+
+func FlashWarning(c *gin.Context, message string) {
+	addFlash(c, func(f *Flash) { f.Warning = message })
+}
+
+// FlashFailure flashes message for the page the handler redirects to and
+// attaches err to the request, so RequestLogger logs the cause
+func FlashFailure(c *gin.Context, err error, message string) {
+	_ = c.Error(err)
+	FlashError(c, message)
+}
+
+// FlashInput keeps the validation errors and the submitted form for the page
+// the handler redirects back to (post-redirect-get)
+func FlashInput(c *gin.Context, errs map[string]string) {
+	old := url.Values{}
+	for key, values := range c.Request.PostForm {
+		if !logger.IsSensitive(key) {
+			old[key] = values
+		}
+	}
+	addFlash(c, func(f *Flash) {
+		f.Errors = errs
+		f.Old = old
+	})
+}
+
+func addFlash(c *gin.Context, update func(*Flash)) {
+	session := sessions.Default(c)
+	flash, _ := session.Get(flashSessionKey).(Flash)
+	update(&flash)
+	session.Set(flashSessionKey, flash)
+	if err := session.Save(); err != nil {
+		// The redirect still happens, only the message is lost
+		slog.WarnContext(c.Request.Context(), "Failed to save flash message", "error", err)
+	}
+}
+
+// TakeFlash returns the flash of the previous request and removes it from the
+// session; later calls in the same request return the same flash
+func TakeFlash(c *gin.Context) Flash {
+	if cached, ok := c.Get(flashContextKey); ok {
+		return cached.(Flash)
+	}
+
+	var flash Flash
+	if _, ok := c.Get(sessions.DefaultKey); ok { // pages outside /admin have no session
+		session := sessions.Default(c)
+		if stored, ok := session.Get(flashSessionKey).(Flash); ok {
+			flash = stored
+			session.Delete(flashSessionKey)
+			if err := session.Save(); err != nil {
+				slog.WarnContext(c.Request.Context(), "Failed to clear flash message", "error", err)
+			}
+		}
+	}
+	c.Set(flashContextKey, flash)
+	return flash
+}
+
+// OldInput fills obj (a DTO with form tags) with the input kept by FlashInput.
+// It reports whether there was any, so edit pages can fall back to the record.
+func OldInput(c *gin.Context, obj any) bool {
+	old := TakeFlash(c).Old
+	if len(old) == 0 {
+		return false
+	}
+	return binding.MapFormWithTag(obj, old, "form") == nil
+}
+
+// addFlashData exposes the flash to templates as success, error, warning,
+// errors and old, unless the handler set these keys itself
+func addFlashData(c *gin.Context, data gin.H) {
+	flash := TakeFlash(c)
+	for key, value := range map[string]any{
+		"success": flash.Success,
+		"error":   flash.Error,
+		"warning": flash.Warning,
+	} {
+		if _, ok := data[key]; !ok && value != "" {
+			data[key] = value
+		}
+	}
+	if _, ok := data["errors"]; !ok && len(flash.Errors) > 0 {
+		data["errors"] = flash.Errors
+	}
+	if _, ok := data["old"]; !ok {
+		data["old"] = flash.Old
+	}
+}
@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-app/config"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pressly/goose"
+)
+
+// Each readiness check gets this long before it counts as down
+const healthCheckTimeout = 2 * time.Second
+
+// Check statuses
+const (
+	HealthOK   = "ok"
+	HealthDown = "down"
+)
+
+// HealthCheck is the result of one dependency check of /readyz
+type HealthCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	Pending   *int    `json:"pending,omitempty"` // migrations only
+}
+
+// Readiness runs the Postgres, Redis and migration checks concurrently;
+// ready is false when any of them failed
+func Readiness(ctx context.Context) (checks map[string]HealthCheck, ready bool) {
+	probes := map[string]func(context.Context) (*int, error){
+		"postgres": func(ctx context.Context) (*int, error) {
+			return nil, config.DB.PingContext(ctx)
+		},
+		"redis": func(ctx context.Context) (*int, error) {
+			return nil, config.RedisClient.Ping(ctx).Err()
+		},
+		"migrations": func(ctx context.Context) (*int, error) {
+			pending, err := PendingMigrations(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if pending > 0 {
+				return &pending, fmt.Errorf("%d pending migration(s)", pending)
+			}
+			return &pending, nil
+		},
+	}
+
+	checks = make(map[string]HealthCheck, len(probes))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			check := runHealthCheck(ctx, probe)
+
+			mu.Lock()
+			checks[name] = check
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	ready = true
+	for _, check := range checks {
+		ready = ready && check.Status == HealthOK
+	}
+	return checks, ready
+}
+
+func runHealthCheck(ctx context.Context, probe func(context.Context) (*int, error)) HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	pending, err := probe(ctx)
+	check := HealthCheck{
+		Status:    HealthOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		Pending:   pending,
+	}
+	if err != nil {
+		check.Status = HealthDown
+		check.Error = err.Error()
+	}
+	return check
+}
+
+// PendingMigrations counts the migrations of database.migrations_dir newer than
+// the applied goose version. Unlike goose itself it never creates the version table.
+func PendingMigrations(ctx context.Context) (int, error) {
+	current, err := appliedMigrationVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	migrations, err := goose.CollectMigrations(config.AppConfig.Database.MigrationsDir, current, math.MaxInt64)
+	if err != nil {
+		return 0, err
+	}
+	return len(migrations), nil
+}
+
+// appliedMigrationVersion reads the current version the way goose does: the
+// newest row of each version says whether it is applied or rolled back
+func appliedMigrationVersion(ctx context.Context) (int64, error) {
+	rows, err := config.DB.QueryContext(ctx, "SELECT version_id, is_applied FROM "+goose.TableName()+" ORDER BY id DESC")
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "42P01" {
+		return 0, nil // undefined_table: nothing migrated yet
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	rolledBack := map[int64]bool{}
+	for rows.Next() {
+		var version int64
+		var applied bool
+		if err := rows.Scan(&version, &applied); err != nil {
+			return 0, err
+		}
+		if rolledBack[version] {
+			continue
+		}
+		if applied {
+			return version, nil
+		}
+		rolledBack[version] = true
+	}
+	return 0, rows.Err()
+}
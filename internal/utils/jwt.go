@@ -1,23 +1,144 @@
 package utils
 
 import (
+	"context"
+	"errors"
+	"gin-app/config"
+	"gin-app/internal/models"
+	"gin-app/internal/pkg/jwtkeys"
+	"log/slog"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func GenerateToken(userID int64, secret string, ttl time.Duration) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(ttl).Unix(),
-		"iat":     time.Now().Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+// How often the keys directory is re-read (rotation by other instances / the CLI)
+const jwtKeysReloadInterval = time.Minute
+
+var jwtKeys *jwtkeys.Manager
+
+// LoadJWTKeys opens the keys directory of the config, generating a signing key if needed
+func LoadJWTKeys() (*jwtkeys.Manager, error) {
+	conf := config.AppConfig.Jwt
+
+	return jwtkeys.New(jwtkeys.Options{
+		Dir:              conf.KeysDir,
+		Algorithm:        conf.Algorithm,
+		RotationInterval: conf.RotationInterval,
+		GracePeriod:      conf.GracePeriod,
+	})
 }
 
-func ParseToken(tokenStr string, secret string) (*jwt.Token, error) {
-	return jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
+// InitJWTKeys loads the signing keys and keeps rotating them in the background until ctx is done
+func InitJWTKeys(ctx context.Context) {
+	manager, err := LoadJWTKeys()
+	if err != nil {
+		slog.Error("Failed to load JWT keys", "error", err)
+		os.Exit(1)
+	}
+	jwtKeys = manager
+
+	go manager.Run(ctx, jwtKeysReloadInterval, func(err error) {
+		slog.Error("JWT key rotation failed", "error", err)
 	})
+
+	current, _ := manager.Current()
+	slog.Info("JWT keys loaded", "algorithm", current.Algorithm, "kid", current.ID)
+}
+
+// JWKS returns the public keys for /.well-known/jwks.json
+func JWKS() jwtkeys.JWKSet {
+	return jwtKeys.JWKS()
+}
+
+// Token types (typ claim)
+const (
+	AccessTokenType  = "access"
+	RefreshTokenType = "refresh"
+)
+
+var (
+	ErrWrongTokenType = errors.New("wrong token type")
+	ErrInvalidSubject = errors.New("invalid token subject")
+)
+
+// Claims of the access and refresh tokens
+type Claims struct {
+	jwt.RegisteredClaims        // iss, sub (user ID), aud (scope), exp, iat, jti
+	Type                 string `json:"typ"`
+	Role                 string `json:"role,omitempty"` // at issue time; authorization always reads the database
+}
+
+// UserID returns the user the token was issued for
+func (c *Claims) UserID() (int64, error) {
+	id, err := strconv.ParseInt(c.Subject, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidSubject
+	}
+	return id, nil
+}
+
+// TokenIssuer is the iss claim: jwt.issuer, or app.url when not set
+func TokenIssuer() string {
+	if config.AppConfig.Jwt.Issuer != "" {
+		return config.AppConfig.Jwt.Issuer
+	}
+	return config.AppConfig.App.URL
+}
+
+// GenerateToken signs a token of the given type for the scope's audience
+func GenerateToken(scope TokenScope, typ string, user *models.User, ttl time.Duration) (string, error) {
+	key, err := jwtKeys.Current()
+	if err != nil {
+		return "", err
+	}
+
+	// jti keeps tokens unique even when issued in the same second
+	jti, err := RandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    TokenIssuer(),
+			Subject:   strconv.FormatInt(user.ID, 10),
+			Audience:  jwt.ClaimStrings{scope.Audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        jti,
+		},
+		Type: typ,
+		Role: user.Role,
+	}
+	token := jwt.NewWithClaims(key.SigningMethod(), claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+// ParseToken verifies the signature with the key named by the kid header (only
+// RS256/EdDSA, and only with a key of that algorithm), then the expiry, issuer,
+// the scope's audience and the token type.
+func ParseToken(tokenStr string, scope TokenScope, typ string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, jwtKeys.Keyfunc,
+		jwt.WithValidMethods(jwtkeys.Algorithms()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithIssuer(TokenIssuer()),
+		jwt.WithAudience(scope.Audience),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != typ {
+		return nil, ErrWrongTokenType
+	}
+	if _, err := claims.UserID(); err != nil {
+		return nil, err
+	}
+	return claims, nil
 }
@@ -0,0 +1,243 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gin-app/config"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Login brute-force limits
+const (
+	LoginFailureWindow = 15 * time.Minute // sliding window for failure counters
+
+	MaxLoginFailuresPerIP      = 20
+	MaxLoginFailuresPerAccount = 5 // then the account is locked
+	LoginLockoutDuration       = 15 * time.Minute
+
+	// Failures per account before delays kick in; each further failure doubles the delay
+	loginDelayAfter = 1
+	maxLoginDelay   = 30 * time.Second
+)
+
+const (
+	loginFailIPPrefix      = "login_fail_ip:"
+	loginFailAccountPrefix = "login_fail_account:"
+	loginDelayPrefix       = "login_delay:"
+	loginLockPrefix        = "login_lock:"
+)
+
+var (
+	ErrLoginLocked    = errors.New("account temporarily locked")
+	ErrLoginThrottled = errors.New("too many login attempts")
+)
+
+// LoginLimitError tells the caller how long to wait before the next attempt
+type LoginLimitError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginLimitError) Error() string { return e.Err.Error() }
+func (e *LoginLimitError) Unwrap() error { return e.Err }
+
+// Message is shown on the login form / returned by the API
+func (e *LoginLimitError) Message() string {
+	wait := humanizeWait(e.RetryAfter)
+	if errors.Is(e.Err, ErrLoginLocked) {
+		return "Too many failed attempts, this account is locked. Try again in " + wait + "."
+	}
+	return "Too many login attempts. Try again in " + wait + "."
+}
+
+// LoginLock is a locked account as shown in the admin panel
+type LoginLock struct {
+	Email     string
+	Failures  int64
+	ExpiresIn time.Duration
+}
+
+// Wait is the remaining lock time in words
+func (l LoginLock) Wait() string {
+	return humanizeWait(l.ExpiresIn)
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CheckLoginAllowed returns a *LoginLimitError when the IP or account may not try to log in right now
+func CheckLoginAllowed(ctx context.Context, ip, email string) error {
+	email = normalizeLoginEmail(email)
+
+	if ttl, _ := config.RedisClient.PTTL(ctx, loginLockPrefix+email).Result(); ttl > 0 {
+		return &LoginLimitError{Err: ErrLoginLocked, RetryAfter: ttl}
+	}
+
+	if ttl, _ := config.RedisClient.PTTL(ctx, loginDelayPrefix+email).Result(); ttl > 0 {
+		return &LoginLimitError{Err: ErrLoginThrottled, RetryAfter: ttl}
+	}
+
+	count, oldest, err := windowCount(ctx, loginFailIPPrefix+ip)
+	if err != nil {
+		return err
+	}
+	if count >= MaxLoginFailuresPerIP {
+		return &LoginLimitError{Err: ErrLoginThrottled, RetryAfter: time.Until(oldest.Add(LoginFailureWindow))}
+	}
+	return nil
+}
+
+// RegisterLoginFailure counts a failed attempt for the IP and the account,
+// applies the progressive delay and locks the account once the limit is hit
+func RegisterLoginFailure(ctx context.Context, ip, email string) error {
+	email = normalizeLoginEmail(email)
+
+	if _, err := windowAdd(ctx, loginFailIPPrefix+ip); err != nil {
+		return err
+	}
+	failures, err := windowAdd(ctx, loginFailAccountPrefix+email)
+	if err != nil {
+		return err
+	}
+
+	if failures >= MaxLoginFailuresPerAccount {
+		return config.RedisClient.Set(ctx, loginLockPrefix+email, failures, LoginLockoutDuration).Err()
+	}
+
+	if failures > loginDelayAfter {
+		delay := time.Second << (failures - loginDelayAfter - 1)
+		if delay > maxLoginDelay {
+			delay = maxLoginDelay
+		}
+		return config.RedisClient.Set(ctx, loginDelayPrefix+email, 1, delay).Err()
+	}
+	return nil
+}
+
+// ResetLoginFailures clears the account counters after a successful login
+func ResetLoginFailures(ctx context.Context, email string) {
+	email = normalizeLoginEmail(email)
+	deleteKeys(ctx, loginFailAccountPrefix+email, loginDelayPrefix+email)
+}
+
+// UnlockLogin removes the lock and the failure history of an account
+func UnlockLogin(ctx context.Context, email string) error {
+	email = normalizeLoginEmail(email)
+	return deleteKeys(ctx, loginLockPrefix+email, loginFailAccountPrefix+email, loginDelayPrefix+email)
+}
+
+// ListLoginLocks returns the currently locked accounts
+func ListLoginLocks(ctx context.Context) ([]LoginLock, error) {
+	var locks []LoginLock
+
+	err := scanKeys(ctx, loginLockPrefix+"*", func(key string) {
+		ttl, err := config.RedisClient.PTTL(ctx, key).Result()
+		if err != nil || ttl <= 0 {
+			return
+		}
+		failures, _ := config.RedisClient.Get(ctx, key).Int64()
+		locks = append(locks, LoginLock{
+			Email:     strings.TrimPrefix(key, loginLockPrefix),
+			Failures:  failures,
+			ExpiresIn: ttl,
+		})
+	})
+	return locks, err
+}
+
+// deleteKeys deletes the keys one by one in a pipeline; a single multi-key DEL
+// fails in cluster mode when the keys live in different slots
+func deleteKeys(ctx context.Context, keys ...string) error {
+	pipe := config.RedisClient.Pipeline()
+	for _, key := range keys {
+		pipe.Del(ctx, key)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// scanKeys calls fn for every key matching pattern, on every master in cluster mode
+func scanKeys(ctx context.Context, pattern string, fn func(key string)) error {
+	cluster, ok := config.RedisClient.(*redis.ClusterClient)
+	if !ok {
+		iter := config.RedisClient.Scan(ctx, 0, pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			fn(iter.Val())
+		}
+		return iter.Err()
+	}
+
+	// Masters are scanned concurrently, fn runs afterwards
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+		iter := node.Scan(ctx, 0, pattern, 100).Iterator()
+		for iter.Next(ctx) {
+			mu.Lock()
+			keys = append(keys, iter.Val())
+			mu.Unlock()
+		}
+		return iter.Err()
+	})
+	for _, key := range keys {
+		fn(key)
+	}
+	return err
+}
+
+// windowAdd records an event in a sliding window sorted set and returns the events inside the window
+func windowAdd(ctx context.Context, key string) (int64, error) {
+	now := time.Now()
+	member, err := RandomToken(8)
+	if err != nil {
+		return 0, err
+	}
+
+	pipe := config.RedisClient.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-LoginFailureWindow).UnixMilli(), 10))
+	pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: member})
+	count := pipe.ZCard(ctx, key)
+	pipe.Expire(ctx, key, LoginFailureWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+// windowCount returns the events inside the window and the time of the oldest one
+func windowCount(ctx context.Context, key string) (int64, time.Time, error) {
+	now := time.Now()
+
+	pipe := config.RedisClient.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.Add(-LoginFailureWindow).UnixMilli(), 10))
+	count := pipe.ZCard(ctx, key)
+	oldest := pipe.ZRangeWithScores(ctx, key, 0, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, time.Time{}, err
+	}
+
+	var first time.Time
+	if z := oldest.Val(); len(z) > 0 {
+		first = time.UnixMilli(int64(z[0].Score))
+	}
+	return count.Val(), first, nil
+}
+
+func humanizeWait(d time.Duration) string {
+	if d < time.Second {
+		d = time.Second
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("%d seconds", int(d.Round(time.Second).Seconds()))
+	}
+	return fmt.Sprintf("%d minutes", int((d+time.Minute-1)/time.Minute))
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// MinAdminPasswordLength is the minimum length of every password, whether set
+// from the CLI or the admin panel
+const MinAdminPasswordLength = 12
+
+// commonPasswords are rejected regardless of their character mix
+var commonPasswords = map[string]bool{
+	"password":      true,
+	"password123":   true,
+	"password1234":  true,
+	"admin":         true,
+	"admin123":      true,
+	"administrator": true,
+	"qwerty123456":  true,
+	"123456789012":  true,
+	"letmein12345":  true,
+	"welcome12345":  true,
+}
+
+// ValidatePasswordStrength requires MinAdminPasswordLength characters and at
+// least three of: lowercase, uppercase, digit, symbol
+func ValidatePasswordStrength(password string) error {
+	if len(password) < MinAdminPasswordLength {
+		return errors.New("password must be at least 12 characters")
+	}
+	if len(password) > 72 {
+		return errors.New("password must be at most 72 characters") // bcrypt limit
+	}
+	if commonPasswords[strings.ToLower(password)] {
+		return errors.New("password is too common")
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+
+	classes := 0
+	for _, ok := range []bool{lower, upper, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < 3 {
+		return errors.New("password must mix at least three of: lowercase, uppercase, digits, symbols")
+	}
+	return nil
+}
+
+// PasswordStrengthErrors returns the ValidatePasswordStrength failure as the
+// form error of the Password field, or nil for a strong password
+func PasswordStrengthErrors(password string) map[string]string {
+	err := ValidatePasswordStrength(password)
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	return map[string]string{"Password": strings.ToUpper(msg[:1]) + msg[1:]}
+}
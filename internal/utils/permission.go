@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"gin-app/config"
+	"gin-app/internal/models"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// permissions of the current user, loaded once per request
+const permissionsContextKey = "permissions"
+
+// AuthUser returns the user authenticated by AdminAuthMiddleware or ApiAuthMiddleware
+func AuthUser(c *gin.Context) *models.User {
+	if admin := CurrentAdmin(c); admin != nil {
+		return admin
+	}
+	return CurrentApiUser(c)
+}
+
+// Can reports whether the authenticated user has the permission
+func Can(c *gin.Context, permission string) bool {
+	user := AuthUser(c)
+	if user == nil {
+		return false
+	}
+	if user.Role == models.RoleAdmin {
+		return true
+	}
+
+	perms, ok := c.Get(permissionsContextKey)
+	if !ok {
+		slugs, err := models.GetRolePermissionSlugs(c.Request.Context(), config.DB, user.Role)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "Failed to load permissions", "role", user.Role, "error", err)
+			return false
+		}
+		set := make(map[string]bool, len(slugs))
+		for _, slug := range slugs {
+			set[slug] = true
+		}
+		c.Set(permissionsContextKey, set)
+		perms = set
+	}
+
+	return perms.(map[string]bool)[permission]
+}
@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSON API envelopes
+//
+//	success: {"success": true, "message": "...", "data": ..., "meta": {...}}
+//	error:   {"success": false, "message": "...", "errors": {"Field": "msg"}}
+
+// RespondSuccess writes a success envelope. meta is optional (e.g. pagination)
+func RespondSuccess(c *gin.Context, status int, message string, data interface{}, meta ...gin.H) {
+	body := gin.H{
+		"success": true,
+		"message": message,
+		"data":    data,
+	}
+	if len(meta) > 0 && meta[0] != nil {
+		body["meta"] = meta[0]
+	}
+	c.JSON(status, body)
+}
+
+// RespondError writes an error envelope. errs usually comes from ValidateStruct
+func RespondError(c *gin.Context, status int, message string, errs map[string]string) {
+	body := gin.H{
+		"success": false,
+		"message": message,
+	}
+	if len(errs) > 0 {
+		body["errors"] = errs
+	}
+	c.JSON(status, body)
+}
+
+// AbortWithError writes an error envelope and stops the middleware chain
+func AbortWithError(c *gin.Context, status int, message string) {
+	RespondError(c, status, message, nil)
+	c.Abort()
+}
+
+// WantsJSON reports whether the client expects a JSON response (API routes and AJAX calls)
+func WantsJSON(c *gin.Context) bool {
+	if strings.HasPrefix(c.Request.URL.Path, "/api/") {
+		return true
+	}
+	if c.ContentType() == gin.MIMEJSON || c.GetHeader("X-Requested-With") == "XMLHttpRequest" {
+		return true
+	}
+	return strings.Contains(c.GetHeader("Accept"), gin.MIMEJSON)
+}
@@ -0,0 +1,501 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"gin-app/config"
+	"gin-app/internal/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// AccessTTL is the lifetime of access tokens (app.token_ttl)
+func AccessTTL() time.Duration {
+	return config.AppConfig.App.TokenTTL
+}
+
+// RefreshTTL is the lifetime of refresh tokens (app.refresh_ttl)
+func RefreshTTL() time.Duration {
+	return config.AppConfig.App.RefreshTTL
+}
+
+// RememberRefreshTTL is the refresh token lifetime of an admin login with "remember me" (app.remember_ttl)
+func RememberRefreshTTL() time.Duration {
+	return config.AppConfig.App.RememberTTL
+}
+
+// TokenScope holds the Redis key prefixes of one kind of client
+type TokenScope struct {
+	Name     string // "admin" or "api", used in URLs of the sessions page
+	Audience string // aud claim of the tokens
+
+	Access       string
+	Refresh      string
+	RefreshUsed  string // rotated refresh tokens, kept to detect reuse
+	RefreshGrace string
+
+	Session      string // session hash: user, device and current tokens
+	UserSessions string // set of session IDs per user
+
+	// Parallel requests that carry the same refresh token (e.g. several AJAX calls
+	// after the access token expired) are not treated as reuse within this window.
+	GracePeriod time.Duration
+}
+
+var (
+	// AdminScope is used by the cookie based admin panel
+	AdminScope = TokenScope{
+		Name:         "admin",
+		Audience:     "admin",
+		Access:       "admin_access:",
+		Refresh:      "admin_refresh:",
+		RefreshUsed:  "admin_refresh_used:",
+		RefreshGrace: "admin_refresh_grace:",
+		Session:      "admin_session:",
+		UserSessions: "admin_user_sessions:",
+		GracePeriod:  15 * time.Second,
+	}
+
+	// ApiScope is used by Bearer token clients of /api/v1
+	ApiScope = TokenScope{
+		Name:         "api",
+		Audience:     "api",
+		Access:       "api_access:",
+		Refresh:      "api_refresh:",
+		RefreshUsed:  "api_refresh_used:",
+		RefreshGrace: "api_refresh_grace:",
+		Session:      "api_session:",
+		UserSessions: "api_user_sessions:",
+	}
+
+	// TokenScopes lists every scope, e.g. to revoke all sessions of a user
+	TokenScopes = []TokenScope{AdminScope, ApiScope}
+)
+
+// ScopeByName returns the scope with the given Name
+func ScopeByName(name string) (TokenScope, bool) {
+	for _, scope := range TokenScopes {
+		if scope.Name == name {
+			return scope, true
+		}
+	}
+	return TokenScope{}, false
+}
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+)
+
+// Context keys of the session ID behind the current request
+const (
+	AdminSessionContextKey = "admin_session"
+	ApiSessionContextKey   = "api_session"
+)
+
+// TokenPair is a freshly issued access/refresh pair
+type TokenPair struct {
+	SessionID    string
+	AccessToken  string
+	RefreshToken string
+	AccessTTL    time.Duration
+	RefreshTTL   time.Duration
+}
+
+// SessionRef is what a token key points to: "<user id>:<session id>"
+type SessionRef struct {
+	UserID int64
+	ID     string
+}
+
+func (r SessionRef) value() string {
+	return strconv.FormatInt(r.UserID, 10) + ":" + r.ID
+}
+
+func parseSessionRef(val string) (SessionRef, error) {
+	uid, sid, _ := strings.Cut(val, ":")
+	userID, err := strconv.ParseInt(uid, 10, 64)
+	return SessionRef{UserID: userID, ID: sid}, err
+}
+
+// SessionMeta describes the device a session was opened from
+type SessionMeta struct {
+	IP        string
+	UserAgent string
+}
+
+// SessionMetaFrom reads the client IP and user agent of the request
+func SessionMetaFrom(c *gin.Context) SessionMeta {
+	return SessionMeta{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// Session is one logged in device as listed on the sessions page
+type Session struct {
+	ID        string
+	Scope     string
+	UserID    int64
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+	LastSeen  time.Time
+	ExpiresIn time.Duration
+	Current   bool
+}
+
+// CreateSession opens a new session for the user and issues its first token pair
+func CreateSession(ctx context.Context, scope TokenScope, user *models.User, meta SessionMeta, accessTTL, refreshTTL time.Duration) (*TokenPair, error) {
+	sid, err := RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := SessionRef{UserID: user.ID, ID: sid}
+	pair, err := storeTokens(ctx, scope, user, ref, accessTTL, refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	userKey := scope.UserSessions + strconv.FormatInt(user.ID, 10)
+
+	pipe := config.RedisClient.TxPipeline()
+	pipe.HSet(ctx, scope.Session+sid,
+		"user_id", user.ID,
+		"ip", meta.IP,
+		"user_agent", meta.UserAgent,
+		"created_at", now,
+		"last_seen", now,
+		"access", pair.AccessToken,
+		"refresh", pair.RefreshToken,
+	)
+	pipe.Expire(ctx, scope.Session+sid, refreshTTL)
+	pipe.SAdd(ctx, userKey, sid)
+	pipe.Expire(ctx, userKey, RememberRefreshTTL()) // outlives every session of the set
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// storeTokens creates an access/refresh pair of the session and adds it to the Redis allow-list of the scope
+func storeTokens(ctx context.Context, scope TokenScope, user *models.User, ref SessionRef, accessTTL, refreshTTL time.Duration) (*TokenPair, error) {
+	accessToken, err := GenerateToken(scope, AccessTokenType, user, accessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := GenerateToken(scope, RefreshTokenType, user, refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := config.RedisClient.Set(ctx, scope.Access+accessToken, ref.value(), accessTTL).Err(); err != nil {
+		return nil, err
+	}
+	if err := config.RedisClient.Set(ctx, scope.Refresh+refreshToken, ref.value(), refreshTTL).Err(); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		SessionID:    ref.ID,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		AccessTTL:    accessTTL,
+		RefreshTTL:   refreshTTL,
+	}, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new pair of the same session.
+// The new refresh token keeps the remaining lifetime of the old one. Replaying a
+// token that was already rotated revokes every session of that user.
+//
+// Within the scope's grace period a just-rotated token returns the session with a
+// nil pair: the request is authenticated but no new tokens are issued.
+func RotateRefreshToken(ctx context.Context, scope TokenScope, refreshToken, accessToken string) (SessionRef, *TokenPair, error) {
+	// An access token or a token of the other scope is never a refresh token
+	if _, err := ParseToken(refreshToken, scope, RefreshTokenType); err != nil {
+		return SessionRef{}, nil, ErrInvalidRefreshToken
+	}
+
+	key := scope.Refresh + refreshToken
+
+	ttl, err := config.RedisClient.TTL(ctx, key).Result()
+	if err != nil {
+		return SessionRef{}, nil, err
+	}
+
+	// GETDEL so two requests can't rotate the same token
+	val, err := config.RedisClient.GetDel(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		// Rotated moments ago by a parallel request
+		if grace, err := config.RedisClient.Get(ctx, scope.RefreshGrace+refreshToken).Result(); err == nil {
+			ref, err := parseSessionRef(grace)
+			if err != nil {
+				return SessionRef{}, nil, ErrInvalidRefreshToken
+			}
+			// The session may have been revoked or the user disabled since
+			if _, err := activeSessionUser(ctx, scope, ref); err != nil {
+				return SessionRef{}, nil, err
+			}
+			return ref, nil, nil
+		}
+
+		used, err := config.RedisClient.Get(ctx, scope.RefreshUsed+refreshToken).Result()
+		if err != nil {
+			return SessionRef{}, nil, ErrInvalidRefreshToken
+		}
+		ref, _ := parseSessionRef(used)
+		if err := RevokeUserTokens(ctx, ref.UserID); err != nil {
+			return ref, nil, err
+		}
+		return ref, nil, ErrRefreshTokenReused
+	}
+	if err != nil {
+		return SessionRef{}, nil, err
+	}
+
+	ref, err := parseSessionRef(val)
+	if err != nil {
+		return SessionRef{}, nil, ErrInvalidRefreshToken
+	}
+
+	// Fresh role claim for the new pair
+	user, err := activeSessionUser(ctx, scope, ref)
+	if err != nil {
+		return SessionRef{}, nil, err
+	}
+
+	if ttl <= 0 {
+		ttl = RefreshTTL()
+	}
+	config.RedisClient.Set(ctx, scope.RefreshUsed+refreshToken, ref.value(), ttl)
+	if scope.GracePeriod > 0 {
+		config.RedisClient.Set(ctx, scope.RefreshGrace+refreshToken, ref.value(), scope.GracePeriod)
+	}
+
+	// Old access token goes away with its refresh token
+	if accessToken != "" {
+		config.RedisClient.Del(ctx, scope.Access+accessToken)
+	}
+
+	pair, err := storeTokens(ctx, scope, user, ref, AccessTTL(), ttl)
+	if err != nil {
+		return ref, nil, err
+	}
+
+	if ref.ID != "" {
+		config.RedisClient.HSet(ctx, scope.Session+ref.ID,
+			"access", pair.AccessToken,
+			"refresh", pair.RefreshToken,
+			"last_seen", time.Now().Unix(),
+		)
+		config.RedisClient.Expire(ctx, scope.Session+ref.ID, ttl)
+	}
+	return ref, pair, nil
+}
+
+// activeSessionUser loads the user of a session that still exists (it may have
+// been revoked from the sessions page) and may still log in
+func activeSessionUser(ctx context.Context, scope TokenScope, ref SessionRef) (*models.User, error) {
+	if ref.ID != "" {
+		if exists, _ := config.RedisClient.Exists(ctx, scope.Session+ref.ID).Result(); exists == 0 {
+			return nil, ErrInvalidRefreshToken
+		}
+	}
+
+	user, err := models.GetUserByID(ctx, config.DB, ref.UserID)
+	if err != nil || !user.IsActive() {
+		return nil, ErrInvalidRefreshToken
+	}
+	return user, nil
+}
+
+// LookupAccessToken returns the user and session of a valid access token:
+// the JWT must verify as an access token of the scope and still be on the allow-list
+func LookupAccessToken(ctx context.Context, scope TokenScope, accessToken string) (SessionRef, error) {
+	claims, err := ParseToken(accessToken, scope, AccessTokenType)
+	if err != nil {
+		return SessionRef{}, err
+	}
+
+	val, err := config.RedisClient.Get(ctx, scope.Access+accessToken).Result()
+	if errors.Is(err, redis.Nil) {
+		return SessionRef{}, ErrTokenRevoked
+	}
+	if err != nil {
+		return SessionRef{}, err
+	}
+	ref, err := parseSessionRef(val)
+	if err != nil {
+		return SessionRef{}, err
+	}
+	if userID, _ := claims.UserID(); userID != ref.UserID {
+		return SessionRef{}, ErrInvalidSubject
+	}
+	return ref, nil
+}
+
+// TouchSession records activity of a session (throttled to once a minute)
+func TouchSession(ctx context.Context, scope TokenScope, sessionID, ip string) {
+	if sessionID == "" {
+		return
+	}
+	key := scope.Session + sessionID
+	last, _ := config.RedisClient.HGet(ctx, key, "last_seen").Int64()
+	if time.Since(time.Unix(last, 0)) < time.Minute {
+		return
+	}
+	config.RedisClient.HSet(ctx, key, "last_seen", time.Now().Unix(), "ip", ip)
+}
+
+// RevokeTokens ends the session behind an access and/or refresh token
+func RevokeTokens(ctx context.Context, scope TokenScope, accessToken, refreshToken string) {
+	for _, key := range []string{scope.Access + accessToken, scope.Refresh + refreshToken} {
+		if strings.HasSuffix(key, ":") {
+			continue
+		}
+		if val, err := config.RedisClient.Get(ctx, key).Result(); err == nil {
+			if ref, err := parseSessionRef(val); err == nil && ref.ID != "" {
+				_ = RevokeSession(ctx, scope, ref.UserID, ref.ID)
+			}
+		}
+	}
+
+	// Tokens issued before sessions were indexed
+	if accessToken != "" {
+		config.RedisClient.Del(ctx, scope.Access+accessToken)
+	}
+	if refreshToken != "" {
+		config.RedisClient.Del(ctx, scope.Refresh+refreshToken)
+	}
+}
+
+// RevokeSession deletes a session of the user together with its tokens
+func RevokeSession(ctx context.Context, scope TokenScope, userID int64, sessionID string) error {
+	key := scope.Session + sessionID
+
+	vals, err := config.RedisClient.HGetAll(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if len(vals) == 0 || vals["user_id"] != strconv.FormatInt(userID, 10) {
+		config.RedisClient.SRem(ctx, scope.UserSessions+strconv.FormatInt(userID, 10), sessionID)
+		return ErrSessionNotFound
+	}
+
+	pipe := config.RedisClient.TxPipeline()
+	if vals["access"] != "" {
+		pipe.Del(ctx, scope.Access+vals["access"])
+	}
+	if vals["refresh"] != "" {
+		pipe.Del(ctx, scope.Refresh+vals["refresh"])
+	}
+	pipe.Del(ctx, key)
+	pipe.SRem(ctx, scope.UserSessions+strconv.FormatInt(userID, 10), sessionID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// ListSessions returns the live sessions of the user in the scope, newest first
+func ListSessions(ctx context.Context, scope TokenScope, userID int64) ([]Session, error) {
+	userKey := scope.UserSessions + strconv.FormatInt(userID, 10)
+
+	ids, err := config.RedisClient.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(ids))
+	for _, sid := range ids {
+		vals, err := config.RedisClient.HGetAll(ctx, scope.Session+sid).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(vals) == 0 {
+			config.RedisClient.SRem(ctx, userKey, sid) // expired
+			continue
+		}
+
+		created, _ := strconv.ParseInt(vals["created_at"], 10, 64)
+		lastSeen, _ := strconv.ParseInt(vals["last_seen"], 10, 64)
+		ttl, _ := config.RedisClient.TTL(ctx, scope.Session+sid).Result()
+		sessions = append(sessions, Session{
+			ID:        sid,
+			Scope:     scope.Name,
+			UserID:    userID,
+			IP:        vals["ip"],
+			UserAgent: vals["user_agent"],
+			CreatedAt: time.Unix(created, 0),
+			LastSeen:  time.Unix(lastSeen, 0),
+			ExpiresIn: ttl,
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastSeen.After(sessions[j].LastSeen) })
+	return sessions, nil
+}
+
+// RevokeUserTokens ends every admin and API session of the user ("log out everywhere")
+func RevokeUserTokens(ctx context.Context, userID int64) error {
+	for _, scope := range TokenScopes {
+		ids, err := config.RedisClient.SMembers(ctx, scope.UserSessions+strconv.FormatInt(userID, 10)).Result()
+		if err != nil {
+			return err
+		}
+		for _, sid := range ids {
+			if err := RevokeSession(ctx, scope, userID, sid); err != nil && !errors.Is(err, ErrSessionNotFound) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IssueAdminTokens opens an admin session for the request's device and sets the cookies
+func IssueAdminTokens(c *gin.Context, admin *models.User, accessTTL, refreshTTL time.Duration) error {
+	pair, err := CreateSession(c.Request.Context(), AdminScope, admin, SessionMetaFrom(c), accessTTL, refreshTTL)
+	if err != nil {
+		return err
+	}
+	setAdminCookies(c, pair)
+
+	// A token planted before the login must not survive it
+	_, err = RotateCsrfToken(c)
+	return err
+}
+
+// RotateAdminRefreshToken rotates the admin refresh cookie and resets both cookies
+func RotateAdminRefreshToken(c *gin.Context, refreshToken string) (SessionRef, error) {
+	accessToken, _ := c.Cookie("admin_access")
+
+	ref, pair, err := RotateRefreshToken(c.Request.Context(), AdminScope, refreshToken, accessToken)
+	if err != nil {
+		return ref, err
+	}
+	if pair != nil {
+		setAdminCookies(c, pair)
+	}
+	return ref, nil
+}
+
+// LookupAdminAccessToken returns the user and session of a valid admin access token
+func LookupAdminAccessToken(ctx context.Context, accessToken string) (SessionRef, error) {
+	return LookupAccessToken(ctx, AdminScope, accessToken)
+}
+
+func setAdminCookies(c *gin.Context, pair *TokenPair) {
+	SetCookie(c, "admin_access", pair.AccessToken, int(pair.AccessTTL.Seconds()))
+	SetCookie(c, "admin_refresh", pair.RefreshToken, int(pair.RefreshTTL.Seconds()))
+}
+
+// ClearAdminCookies removes the auth cookies from the browser
+func ClearAdminCookies(c *gin.Context) {
+	ClearCookie(c, "admin_access")
+	ClearCookie(c, "admin_refresh")
+}
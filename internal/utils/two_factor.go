@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"gin-app/config"
+	"gin-app/internal/models"
+	"gin-app/internal/pkg/totp"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Two-factor login state
+const (
+	TwoFactorPendingTTL     = 5 * time.Minute  // time to enter the code after the password
+	TwoFactorSetupTTL       = 15 * time.Minute // time to confirm a freshly generated secret
+	MaxTwoFactorAttempts    = 5
+	twoFactorPendingCookie  = "admin_2fa"
+	twoFactorPendingPrefix  = "admin_2fa_pending:"
+	twoFactorSetupPrefix    = "admin_2fa_setup:"
+	twoFactorUsedStepPrefix = "admin_2fa_used:"
+)
+
+var ErrNoPendingLogin = errors.New("no pending two-factor login")
+
+// PendingLogin is a login that passed the password check and waits for the second factor
+type PendingLogin struct {
+	Token    string
+	UserID   int64
+	Remember bool
+}
+
+// StartPendingLogin stores the half-finished login in Redis and hands the browser an opaque cookie.
+// No access/refresh tokens exist until the second factor is verified.
+func StartPendingLogin(c *gin.Context, userID int64, remember bool) error {
+	token, err := RandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	key := twoFactorPendingPrefix + token
+	pipe := config.RedisClient.TxPipeline()
+	pipe.HSet(c.Request.Context(), key, "user_id", userID, "remember", remember, "attempts", 0)
+	pipe.Expire(c.Request.Context(), key, TwoFactorPendingTTL)
+	if _, err := pipe.Exec(c.Request.Context()); err != nil {
+		return err
+	}
+
+	SetCookie(c, twoFactorPendingCookie, token, int(TwoFactorPendingTTL.Seconds()))
+	return nil
+}
+
+// GetPendingLogin returns the pending login of the current browser
+func GetPendingLogin(c *gin.Context) (*PendingLogin, error) {
+	token, _ := c.Cookie(twoFactorPendingCookie)
+	if token == "" {
+		return nil, ErrNoPendingLogin
+	}
+
+	vals, err := config.RedisClient.HGetAll(c.Request.Context(), twoFactorPendingPrefix+token).Result()
+	if err != nil || len(vals) == 0 {
+		return nil, ErrNoPendingLogin
+	}
+
+	userID, err := strconv.ParseInt(vals["user_id"], 10, 64)
+	if err != nil {
+		return nil, ErrNoPendingLogin
+	}
+	return &PendingLogin{Token: token, UserID: userID, Remember: vals["remember"] == "1"}, nil
+}
+
+// FailPendingLogin counts a wrong code; after MaxTwoFactorAttempts the pending login is dropped
+func FailPendingLogin(c *gin.Context, pending *PendingLogin) (remaining int64) {
+	attempts, err := config.RedisClient.HIncrBy(c.Request.Context(), twoFactorPendingPrefix+pending.Token, "attempts", 1).Result()
+	if err != nil || attempts >= MaxTwoFactorAttempts {
+		ClearPendingLogin(c, pending)
+		return 0
+	}
+	return MaxTwoFactorAttempts - attempts
+}
+
+// ClearPendingLogin removes the pending login and its cookie
+func ClearPendingLogin(c *gin.Context, pending *PendingLogin) {
+	if pending != nil {
+		config.RedisClient.Del(c.Request.Context(), twoFactorPendingPrefix+pending.Token)
+	}
+	ClearCookie(c, twoFactorPendingCookie)
+}
+
+// VerifyTOTP checks a code against the secret and rejects a code that was already used
+// within its validity window
+func VerifyTOTP(ctx context.Context, userID int64, secret, code string) bool {
+	step, ok := totp.Validate(secret, code, time.Now())
+	if !ok {
+		return false
+	}
+
+	key := twoFactorUsedStepPrefix + strconv.FormatInt(userID, 10) + ":" + strconv.FormatInt(step, 10)
+	ttl := time.Duration(2*totp.Skew+1) * totp.Period
+	fresh, err := config.RedisClient.SetNX(ctx, key, 1, ttl).Result()
+	return err == nil && fresh
+}
+
+// VerifySecondFactor accepts a TOTP code or an unused recovery code of the user
+func VerifySecondFactor(ctx context.Context, user *models.User, code string) bool {
+	code = strings.TrimSpace(code)
+	if len(strings.ReplaceAll(code, " ", "")) == totp.Digits {
+		return VerifyTOTP(ctx, user.ID, user.TotpSecret, code)
+	}
+
+	ok, err := models.UseRecoveryCode(ctx, config.DB, user.ID, code)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to check recovery code", "user_id", user.ID, "error", err)
+		return false
+	}
+	return ok
+}
+
+// TwoFactorSetupSecret returns the secret being enrolled for the user, creating one if needed
+func TwoFactorSetupSecret(ctx context.Context, userID int64) (string, error) {
+	key := twoFactorSetupPrefix + strconv.FormatInt(userID, 10)
+	if secret, err := config.RedisClient.Get(ctx, key).Result(); err == nil && secret != "" {
+		return secret, nil
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return "", err
+	}
+	if err := config.RedisClient.Set(ctx, key, secret, TwoFactorSetupTTL).Err(); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// ClearTwoFactorSetup drops the enrollment secret once it is confirmed
+func ClearTwoFactorSetup(ctx context.Context, userID int64) {
+	config.RedisClient.Del(ctx, twoFactorSetupPrefix+strconv.FormatInt(userID, 10))
+}
@@ -21,6 +21,35 @@ var FieldMessages = map[string]map[string]string{
 	"CategoryId": {
 		"required": "Category field is required",
 	},
+	"Email": {
+		"required": "Email field is required",
+		"email":    "Email must be a valid email address",
+		"max":      "Email must be at most 255 characters",
+	},
+	"Password": {
+		"required": "Password field is required",
+		"min":      "Password must be at least 12 characters",
+		"max":      "Password must be at most 72 characters",
+	},
+	"PasswordConfirmation": {
+		"required": "Password confirmation field is required",
+		"eqfield":  "Password confirmation does not match",
+	},
+	"Token": {
+		"required": "Reset token is missing",
+	},
+	"Description": {
+		"max": "Description must be at most 255 characters",
+	},
+	"Role": {
+		"required": "Role field is required",
+	},
+	"Code": {
+		"required": "Authentication code is required",
+	},
+	"RefreshToken": {
+		"required": "Refresh token is required",
+	},
 }
 
 // ValidateStruct binds & validates any DTO and returns friendly error messages